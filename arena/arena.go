@@ -0,0 +1,274 @@
+package arena // github.com/sildani/poker-hands-go/arena
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/holdem"
+	"github.com/sildani/poker-hands-go/sim"
+	"io"
+	"math"
+	"sort"
+)
+
+// Base is the rating of an average bot. Every bot is rated as if it had
+// also drawn one game against a bot rated Base, so a bot that has won or
+// lost everything still gets a finite rating.
+const Base = 1500.0
+
+// Resamples is how many times Ratings resamples the matches to put an
+// interval around each rating.
+const Resamples = 200
+
+// Entrant is a bot in the arena. New makes its player for a match from the
+// match's seed, so every match can be played again.
+type Entrant struct {
+	Name string
+	New  func(seed int64) sim.Player
+}
+
+// Config sets up a round-robin. Every pair of entrants plays a heads-up
+// match and, with TableSize over two, every set of TableSize entrants
+// plays a multiway match, or all of them when there are fewer. Tables
+// seat at most ten. All are duplicate matches of Hands hands.
+type Config struct {
+	Game      holdem.Config
+	Stack     int
+	Hands     int
+	TableSize int
+	Seed      int64
+}
+
+// Match is the record of one match, with the players in their seats'
+// order.
+type Match struct {
+	Players  []string  `json:"players"`
+	Hands    int       `json:"hands"`
+	Seed     int64     `json:"seed"`
+	Won      []int     `json:"won"`
+	BBPer100 []float64 `json:"bb_per_100"`
+}
+
+// Rating is a bot's place on the leaderboard. Elo is fitted to the results
+// of every match, Error is the half width of a 95% interval around it and
+// BBPer100 is what the bot won across all its hands.
+type Rating struct {
+	Name     string
+	Elo      float64
+	Error    float64
+	Matches  int
+	Hands    int
+	BBPer100 float64
+}
+
+// RoundRobin plays the matches, each seeded from config.Seed in turn.
+func RoundRobin(config Config, entrants []Entrant) ([]Match, error) {
+	if len(entrants) < 2 {
+		return nil, errors.New("Invalid arena: must have at least two bots")
+	}
+	if config.TableSize > 10 {
+		return nil, errors.New("Invalid arena: tables seat at most ten")
+	}
+	names := map[string]bool{}
+	for _, entrant := range entrants {
+		if names[entrant.Name] {
+			return nil, fmt.Errorf("Invalid arena: bot %q entered twice", entrant.Name)
+		}
+		names[entrant.Name] = true
+	}
+
+	tables := combinations(len(entrants), 2)
+	if size := min(config.TableSize, len(entrants)); size > 2 {
+		tables = append(tables, combinations(len(entrants), size)...)
+	}
+
+	matches := []Match{}
+	for i, table := range tables {
+		seed := config.Seed + int64(i)
+		match := Match{Hands: config.Hands, Seed: seed}
+		players := []sim.Player{}
+		for _, e := range table {
+			match.Players = append(match.Players, entrants[e].Name)
+			players = append(players, entrants[e].New(seed))
+		}
+
+		results, err := sim.Run(sim.Config{Game: config.Game, Stack: config.Stack, Hands: config.Hands, Duplicate: true, Seed: seed}, players)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range results {
+			match.Won = append(match.Won, result.Won)
+			match.BBPer100 = append(match.BBPer100, result.BBPer100)
+		}
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
+
+// combinations lists every set of k of n in order.
+func combinations(n int, k int) [][]int {
+	sets := [][]int{}
+	var walk func(start int, set []int)
+	walk = func(start int, set []int) {
+		if len(set) == k {
+			sets = append(sets, append([]int{}, set...))
+			return
+		}
+		for i := start; i < n; i++ {
+			walk(i+1, append(set, i))
+		}
+	}
+	walk(0, nil)
+	return sets
+}
+
+// Ratings makes the leaderboard, best first. Each match counts as a game
+// between every two of its players, won by whoever won more chips, and the
+// ratings are the Bradley-Terry fit to those games on the Elo scale. The
+// intervals come from fitting matches resampled with rng.
+func Ratings(matches []Match, rng deck.RNG) []Rating {
+	names := []string{}
+	index := map[string]int{}
+	for _, match := range matches {
+		for _, name := range match.Players {
+			if _, ok := index[name]; !ok {
+				index[name] = len(names)
+				names = append(names, name)
+			}
+		}
+	}
+
+	ratings := []Rating{}
+	elos := fit(matches, index)
+	for i, name := range names {
+		ratings = append(ratings, Rating{Name: name, Elo: elos[i]})
+	}
+
+	samples := make([][]float64, len(names))
+	for s := 0; s < Resamples && len(matches) > 0; s++ {
+		resampled := []Match{}
+		for range matches {
+			resampled = append(resampled, matches[rng.Intn(len(matches))])
+		}
+		for i, elo := range fit(resampled, index) {
+			samples[i] = append(samples[i], elo)
+		}
+	}
+	for i := range ratings {
+		mean, variance := 0.0, 0.0
+		for _, elo := range samples[i] {
+			mean += elo
+		}
+		mean /= float64(len(samples[i]))
+		for _, elo := range samples[i] {
+			variance += (elo - mean) * (elo - mean)
+		}
+		if len(samples[i]) > 1 {
+			ratings[i].Error = 1.96 * math.Sqrt(variance/float64(len(samples[i])-1))
+		}
+	}
+
+	won := make([]float64, len(names))
+	for _, match := range matches {
+		for seat, name := range match.Players {
+			i := index[name]
+			ratings[i].Matches++
+			ratings[i].Hands += match.Hands
+			won[i] += match.BBPer100[seat] * float64(match.Hands)
+		}
+	}
+	for i := range ratings {
+		ratings[i].BBPer100 = won[i] / float64(ratings[i].Hands)
+	}
+
+	sort.SliceStable(ratings, func(i, j int) bool {
+		return ratings[i].Elo > ratings[j].Elo
+	})
+	return ratings
+}
+
+// fit rates the bots by the minorization-maximization algorithm for the
+// Bradley-Terry model, draws counting as half a win each, with the one
+// drawn game against a bot rated Base.
+func fit(matches []Match, index map[string]int) []float64 {
+	n := len(index)
+	wins := make([]float64, n)
+	games := make([][]float64, n)
+	for i := range games {
+		wins[i] = 0.5
+		games[i] = make([]float64, n)
+	}
+	for _, match := range matches {
+		for a := range match.Players {
+			for b := range match.Players {
+				if a == b {
+					continue
+				}
+				i, j := index[match.Players[a]], index[match.Players[b]]
+				games[i][j]++
+				if match.Won[a] > match.Won[b] {
+					wins[i]++
+				} else if match.Won[a] == match.Won[b] {
+					wins[i] += 0.5
+				}
+			}
+		}
+	}
+
+	strengths := make([]float64, n)
+	for i := range strengths {
+		strengths[i] = 1
+	}
+	for iteration := 0; iteration < 1000; iteration++ {
+		next := make([]float64, n)
+		for i := range next {
+			played := 1 / (strengths[i] + 1)
+			for j := range games[i] {
+				if games[i][j] > 0 {
+					played += games[i][j] / (strengths[i] + strengths[j])
+				}
+			}
+			next[i] = wins[i] / played
+		}
+		strengths = next
+	}
+
+	elos := []float64{}
+	for _, strength := range strengths {
+		elos = append(elos, Base+400*math.Log10(strength))
+	}
+	return elos
+}
+
+// Save writes the matches as JSON, one a line, so a record can be added to.
+func Save(w io.Writer, matches []Match) error {
+	encoder := json.NewEncoder(w)
+	for _, match := range matches {
+		if err := encoder.Encode(match); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load reads back matches written by Save.
+func Load(r io.Reader) ([]Match, error) {
+	matches := []Match{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var match Match
+		if err := json.Unmarshal(scanner.Bytes(), &match); err != nil {
+			return nil, fmt.Errorf("Invalid record: line %d %v", line, err)
+		}
+		if match.Hands <= 0 || len(match.Players) < 2 || len(match.Won) != len(match.Players) || len(match.BBPer100) != len(match.Players) {
+			return nil, fmt.Errorf("Invalid record: line %d must have hands and a result for each of two or more players", line)
+		}
+		matches = append(matches, match)
+	}
+	return matches, scanner.Err()
+}
@@ -0,0 +1,109 @@
+package arena // github.com/sildani/poker-hands-go/arena
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/sildani/poker-hands-go/bots"
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/holdem"
+	"github.com/sildani/poker-hands-go/sim"
+	"strings"
+	"testing"
+)
+
+var entrants = []Entrant{
+	{Name: "random", New: func(seed int64) sim.Player { return bots.NewRandom(deck.NewSeededRNG(seed)) }},
+	{Name: "station", New: func(seed int64) sim.Player { return bots.CallingStation{} }},
+	{Name: "strength", New: func(seed int64) sim.Player { return bots.NewHandStrength(50, deck.NewSeededRNG(seed)) }},
+}
+
+func TestRoundRobin(t *testing.T) {
+	config := Config{Game: holdem.Config{SmallBlind: 1, BigBlind: 2}, Stack: 200, Hands: 60, TableSize: 3, Seed: 1}
+	matches, err := RoundRobin(config, entrants)
+	if err != nil {
+		t.Fatalf("RoundRobin err == %q but expected nil", err)
+	}
+
+	tables := []string{}
+	for _, match := range matches {
+		tables = append(tables, strings.Join(match.Players, "-"))
+	}
+	if fmt.Sprint(tables) != "[random-station random-strength station-strength random-station-strength]" {
+		t.Errorf("RoundRobin tables == %v but expected every pair then all three", tables)
+	}
+
+	again, _ := RoundRobin(config, entrants)
+	if fmt.Sprint(again) != fmt.Sprint(matches) {
+		t.Errorf("RoundRobin == %v then %v but expected the same seed to play the same", matches, again)
+	}
+
+	if pair, _ := RoundRobin(config, entrants[:2]); len(pair) != 1 {
+		t.Errorf("RoundRobin(two bots) played %d matches but expected the one heads-up match", len(pair))
+	}
+}
+
+func TestRoundRobinValidation(t *testing.T) {
+	config := Config{Game: holdem.Config{SmallBlind: 1, BigBlind: 2}, Stack: 200, Hands: 10}
+	if _, err := RoundRobin(config, entrants[:1]); err == nil || err.Error() != "Invalid arena: must have at least two bots" {
+		t.Errorf("RoundRobin(one bot) err == %v but expected two bots", err)
+	}
+	twice := []Entrant{entrants[0], entrants[0]}
+	if _, err := RoundRobin(config, twice); err == nil || err.Error() != `Invalid arena: bot "random" entered twice` {
+		t.Errorf("RoundRobin(same bot) err == %v but expected entered twice", err)
+	}
+	config.TableSize = 11
+	if _, err := RoundRobin(config, entrants); err == nil || err.Error() != "Invalid arena: tables seat at most ten" {
+		t.Errorf("RoundRobin(TableSize 11) err == %v but expected at most ten", err)
+	}
+}
+
+func TestRatings(t *testing.T) {
+	// a beats b beats c, and a beats c, three times over.
+	matches := []Match{}
+	for i := 0; i < 3; i++ {
+		matches = append(matches,
+			Match{Players: []string{"a", "b"}, Hands: 100, Won: []int{10, -10}, BBPer100: []float64{5, -5}},
+			Match{Players: []string{"b", "c"}, Hands: 100, Won: []int{10, -10}, BBPer100: []float64{5, -5}},
+			Match{Players: []string{"c", "a", "b"}, Hands: 100, Won: []int{-20, 30, -10}, BBPer100: []float64{-10, 15, -5}},
+		)
+	}
+
+	ratings := Ratings(matches, deck.NewSeededRNG(1))
+	order := []string{}
+	for _, rating := range ratings {
+		order = append(order, rating.Name)
+	}
+	if fmt.Sprint(order) != "[a b c]" {
+		t.Errorf("Ratings order == %v but expected [a b c]", order)
+	}
+	if a, c := ratings[0], ratings[2]; a.Elo <= Base || c.Elo >= Base || a.Error <= 0 {
+		t.Errorf("Ratings == %+v but expected a above %v, c below and intervals", ratings, Base)
+	}
+	if b := ratings[1]; b.Matches != 9 || b.Hands != 900 || fmt.Sprintf("%.2f", b.BBPer100) != "-1.67" {
+		t.Errorf("Ratings b == %+v but expected 9 matches of 900 hands at -1.67 bb/100", b)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	matches := []Match{{Players: []string{"a", "b"}, Hands: 10, Seed: 3, Won: []int{4, -4}, BBPer100: []float64{20, -20}}}
+	var record bytes.Buffer
+	Save(&record, matches)
+	Save(&record, matches)
+
+	loaded, err := Load(&record)
+	if err != nil || len(loaded) != 2 || fmt.Sprint(loaded[1]) != fmt.Sprint(matches[0]) {
+		t.Errorf("Load == %v, %v but expected the match twice", loaded, err)
+	}
+
+	for _, test := range []struct {
+		input       string
+		expectedErr string
+	}{
+		{"{", "Invalid record: line 1 unexpected end of JSON input"},
+		{`{"players":["a"],"hands":1,"won":[0],"bb_per_100":[0]}`, "Invalid record: line 1 must have hands and a result for each of two or more players"},
+	} {
+		if _, err := Load(strings.NewReader(test.input)); err == nil || err.Error() != test.expectedErr {
+			t.Errorf("Load(%s) err == %v but expected %q", test.input, err, test.expectedErr)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package audit // github.com/sildani/poker-hands-go/audit
+
+import (
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/evaluator"
+	"github.com/sildani/poker-hands-go/parser"
+	"math"
+)
+
+// The number of five card hands in each category, out of 2,598,960.
+var categoryHands = map[int]float64{
+	evaluator.HighCard:      1302540,
+	evaluator.Pair:          1098240,
+	evaluator.TwoPairs:      123552,
+	evaluator.ThreeOfAKind:  54912,
+	evaluator.Straight:      10200,
+	evaluator.Flush:         5108,
+	evaluator.FullHouse:     3744,
+	evaluator.FourOfAKind:   624,
+	evaluator.StraightFlush: 40,
+}
+
+const fiveCardHands = 2598960
+
+type Result struct {
+	Name             string
+	ChiSquared       float64
+	DegreesOfFreedom int
+	PValue           float64
+	Pass             bool
+}
+
+// Run shuffles a fresh deck shuffles times with rng and runs chi-squared
+// tests on where each card lands, which card follows which, and the
+// categories of the five card hands dealt off the top. A test passes when
+// its p-value is at least alpha.
+func Run(rng deck.RNG, shuffles int, alpha float64) []Result {
+	index := make(map[string]int)
+	for i, card := range parser.Cards() {
+		index[card] = i
+	}
+
+	positions := make([][]float64, 52)
+	followers := make([][]float64, 52)
+	for i := range positions {
+		positions[i] = make([]float64, 52)
+		followers[i] = make([]float64, 52)
+	}
+	categories := make(map[int]float64)
+
+	for i := 0; i < shuffles; i++ {
+		d := deck.New(rng)
+		d.Shuffle()
+		cards, _ := d.Deal(52)
+		for position, card := range cards {
+			positions[index[card]][position]++
+			if position > 0 {
+				followers[index[cards[position-1]]][index[card]]++
+			}
+		}
+		categories[evaluator.ScoreCategory(evaluator.Score(cards[:5]))]++
+	}
+
+	results := []Result{}
+
+	positionChiSquared := 0.0
+	expected := float64(shuffles) / 52
+	for card := range positions {
+		for position := range positions[card] {
+			positionChiSquared += math.Pow(positions[card][position]-expected, 2) / expected
+		}
+	}
+	results = append(results, result("card position", positionChiSquared, 51*51, alpha))
+
+	followerChiSquared := 0.0
+	expected = float64(shuffles) * 51 / (52 * 51)
+	for card := range followers {
+		for follower := range followers[card] {
+			if follower != card {
+				followerChiSquared += math.Pow(followers[card][follower]-expected, 2) / expected
+			}
+		}
+	}
+	results = append(results, result("adjacent pairs", followerChiSquared, 52*51-1, alpha))
+
+	categoryChiSquared := 0.0
+	for category, hands := range categoryHands {
+		expected := float64(shuffles) * hands / fiveCardHands
+		categoryChiSquared += math.Pow(categories[category]-expected, 2) / expected
+	}
+	results = append(results, result("five card categories", categoryChiSquared, len(categoryHands)-1, alpha))
+
+	return results
+}
+
+func result(name string, chiSquared float64, degreesOfFreedom int, alpha float64) Result {
+	pValue := ChiSquaredPValue(chiSquared, degreesOfFreedom)
+	return Result{
+		Name:             name,
+		ChiSquared:       chiSquared,
+		DegreesOfFreedom: degreesOfFreedom,
+		PValue:           pValue,
+		Pass:             pValue >= alpha,
+	}
+}
+
+// ChiSquaredPValue is the chance of a chi-squared statistic at least this
+// large from a fair process: the regularized upper incomplete gamma function
+// Q(k/2, x/2), computed as in Numerical Recipes.
+func ChiSquaredPValue(chiSquared float64, degreesOfFreedom int) float64 {
+	a := float64(degreesOfFreedom) / 2
+	x := chiSquared / 2
+	if x <= 0 {
+		return 1
+	}
+	lgamma, _ := math.Lgamma(a)
+
+	if x < a+1 {
+		// Series for the lower function P, then Q = 1 - P.
+		sum := 1 / a
+		term := sum
+		for n := 1.0; n < 100000; n++ {
+			term *= x / (a + n)
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*1e-15 {
+				break
+			}
+		}
+		return 1 - sum*math.Exp(-x+a*math.Log(x)-lgamma)
+	}
+
+	// Continued fraction for Q (modified Lentz).
+	tiny := 1e-300
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1.0; i < 100000; i++ {
+		an := -i * (i - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return math.Exp(-x+a*math.Log(x)-lgamma) * h
+}
@@ -0,0 +1,65 @@
+package audit // github.com/sildani/poker-hands-go/audit
+
+import (
+	"github.com/sildani/poker-hands-go/deck"
+	"math"
+	"testing"
+)
+
+// biasedRNG makes zero twice as likely as any other number, a classic
+// off-by-one in shuffling code.
+type biasedRNG struct {
+	rng deck.RNG
+}
+
+func (b biasedRNG) Intn(n int) int {
+	value := b.rng.Intn(n + 1)
+	if value == n {
+		return 0
+	}
+	return value
+}
+
+func TestChiSquaredPValue(t *testing.T) {
+	tests := []struct {
+		chiSquared       float64
+		degreesOfFreedom int
+		expectedPValue   float64
+	}{
+		{0, 8, 1},
+		{3.841, 1, 0.05},
+		{6.635, 1, 0.01},
+		{18.307, 10, 0.05},
+		{26.125, 8, 0.001},
+		{2601, 2601, 0.4961},
+	}
+
+	for _, test := range tests {
+		pValue := ChiSquaredPValue(test.chiSquared, test.degreesOfFreedom)
+		if math.Abs(pValue-test.expectedPValue) > 0.0005 {
+			t.Errorf("ChiSquaredPValue(%v, %d) == %.4f but expected %.4f",
+				test.chiSquared, test.degreesOfFreedom, pValue, test.expectedPValue)
+		}
+	}
+}
+
+func TestRunFairShuffle(t *testing.T) {
+	results := Run(deck.NewSeededRNG(1), 20000, 0.001)
+
+	if len(results) != 3 {
+		t.Fatalf("Run returned %d results but expected 3", len(results))
+	}
+	for _, result := range results {
+		if !result.Pass {
+			t.Errorf("Run of a fair shuffle failed %q with p-value %.6f", result.Name, result.PValue)
+		}
+	}
+}
+
+func TestRunBiasedShuffle(t *testing.T) {
+	results := Run(biasedRNG{deck.NewSeededRNG(1)}, 20000, 0.001)
+
+	if results[0].Pass {
+		t.Errorf("Run of a biased shuffle passed %q with p-value %.6f", results[0].Name, results[0].PValue)
+	}
+}
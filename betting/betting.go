@@ -0,0 +1,110 @@
+package betting // github.com/sildani/poker-hands-go/betting
+
+const NoLimit = "no-limit"
+const PotLimit = "pot-limit"
+const FixedLimit = "fixed-limit"
+
+// Round is the state of the betting on one street.
+//
+// BetSize is the smallest bet in no-limit and pot-limit and the only bet in
+// fixed-limit. Cap limits the bets and raises on the street, zero for none.
+// FullBet is the last bet or raise that counted as full: a fixed-limit raise
+// goes to FullBet plus BetSize, which completes a bring-in or a short all in.
+type Round struct {
+	Limit         string
+	BetSize       int
+	Cap           int
+	CurrentBet    int
+	FullBet       int
+	LastRaiseSize int
+	Raises        int
+}
+
+// Player is what the betting needs to know about the player to act.
+// FacedBet is the current bet right after the player last acted.
+type Player struct {
+	Stack    int
+	Bet      int
+	Acted    bool
+	FacedBet int
+}
+
+// Options are the legal actions for the player to act. MinRaise and
+// MaxRaise are totals to bet or raise to, or zero when the player may not.
+type Options struct {
+	Check    bool
+	Call     int
+	MinRaise int
+	MaxRaise int
+}
+
+// NewRound starts a street with no bet yet. An empty limit is no-limit.
+func NewRound(limit string, betSize int, cap int) Round {
+	if limit == "" {
+		limit = NoLimit
+	}
+	return Round{Limit: limit, BetSize: betSize, Cap: cap, LastRaiseSize: betSize}
+}
+
+// Options works out what p may do. pot is everything in the middle,
+// including bets on this street, for pot-limit raises. Nobody may raise
+// when othersCanAct is false, as everyone else still in is all in.
+func (r Round) Options(p Player, pot int, othersCanAct bool) Options {
+	options := Options{Check: p.Bet == r.CurrentBet}
+	if p.Bet < r.CurrentBet {
+		options.Call = min(r.CurrentBet-p.Bet, p.Stack)
+	}
+
+	allIn := p.Stack + p.Bet
+	if allIn <= r.CurrentBet || !othersCanAct || !r.Reopened(p) || (r.Cap > 0 && r.Raises >= r.Cap) {
+		return options
+	}
+
+	switch r.Limit {
+	case FixedLimit:
+		options.MinRaise = min(r.FullBet+r.BetSize, allIn)
+		options.MaxRaise = options.MinRaise
+	case PotLimit:
+		// A pot-sized raise is a call followed by a raise of the pot after
+		// the call.
+		options.MinRaise = min(r.CurrentBet+r.LastRaiseSize, allIn)
+		options.MaxRaise = max(min(r.CurrentBet+pot+r.CurrentBet-p.Bet, allIn), options.MinRaise)
+	default:
+		options.MinRaise = min(r.CurrentBet+r.LastRaiseSize, allIn)
+		options.MaxRaise = allIn
+	}
+	return options
+}
+
+// Reopened is false for a player who has acted unless the bet has gone up
+// by a full raise since: in no-limit and pot-limit the last full raise, in
+// fixed-limit half a bet. So an all in for less does not reopen the
+// betting to them.
+func (r Round) Reopened(p Player) bool {
+	if !p.Acted {
+		return true
+	}
+	if r.Limit == FixedLimit {
+		return 2*(r.CurrentBet-p.FacedBet) >= r.BetSize
+	}
+	return r.CurrentBet-p.FacedBet >= r.LastRaiseSize
+}
+
+// Raise records a bet or raise to a total of to, whether a full one or an
+// all in for less.
+func (r *Round) Raise(to int) {
+	increment := to - r.CurrentBet
+	full := increment >= r.LastRaiseSize
+	if r.Limit == FixedLimit {
+		full = 2*(to-r.FullBet) >= r.BetSize
+	}
+
+	if full {
+		r.Raises++
+		r.FullBet = to
+		if increment > r.LastRaiseSize {
+			r.LastRaiseSize = increment
+		}
+	}
+	r.CurrentBet = to
+}
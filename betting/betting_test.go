@@ -0,0 +1,92 @@
+package betting // github.com/sildani/poker-hands-go/betting
+
+import (
+	"fmt"
+	"testing"
+)
+
+func preflop(limit string, cap int) Round {
+	r := NewRound(limit, 2, cap)
+	r.CurrentBet, r.FullBet, r.Raises = 2, 2, 1
+	return r
+}
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		name            string
+		round           Round
+		player          Player
+		pot             int
+		othersCanAct    bool
+		expectedOptions string
+	}{
+		{"no-limit raise to anything", preflop(NoLimit, 0), Player{Stack: 100}, 3, true, "{false 2 4 100}"},
+		{"no-limit short stack", preflop(NoLimit, 0), Player{Stack: 3}, 3, true, "{false 2 3 3}"},
+		{"no-limit covered by the bet", preflop(NoLimit, 0), Player{Stack: 1}, 3, true, "{false 1 0 0}"},
+		{"no-limit everyone else all in", preflop(NoLimit, 0), Player{Stack: 100}, 3, false, "{false 2 0 0}"},
+		{"pot-limit raise the pot", preflop(PotLimit, 0), Player{Stack: 100}, 3, true, "{false 2 4 7}"},
+		{"pot-limit big blind", preflop(PotLimit, 0), Player{Stack: 98, Bet: 2}, 6, true, "{true 0 4 8}"},
+		{"pot-limit bet the pot", NewRound(PotLimit, 2, 0), Player{Stack: 100}, 10, true, "{true 0 2 10}"},
+		{"pot-limit all in for less", NewRound(PotLimit, 2, 0), Player{Stack: 6}, 10, true, "{true 0 2 6}"},
+		{"fixed-limit raise a bet", preflop(FixedLimit, 4), Player{Stack: 100}, 3, true, "{false 2 4 4}"},
+		{"fixed-limit capped", Round{Limit: FixedLimit, BetSize: 2, Cap: 4, CurrentBet: 8, FullBet: 8, Raises: 4},
+			Player{Stack: 100, Bet: 2}, 20, true, "{false 6 0 0}"},
+		{"fixed-limit uncapped", Round{Limit: FixedLimit, BetSize: 2, CurrentBet: 8, FullBet: 8, Raises: 4},
+			Player{Stack: 100, Bet: 2}, 20, true, "{false 6 10 10}"},
+		{"fixed-limit complete the bring-in", Round{Limit: FixedLimit, BetSize: 10, CurrentBet: 3},
+			Player{Stack: 100}, 3, true, "{false 3 10 10}"},
+	}
+
+	for _, test := range tests {
+		options := test.round.Options(test.player, test.pot, test.othersCanAct)
+		if fmt.Sprint(options) != test.expectedOptions {
+			t.Errorf("%s: Options(%+v) == %v but expected %s", test.name, test.player, options, test.expectedOptions)
+		}
+	}
+}
+
+func TestRaise(t *testing.T) {
+	// No-limit: an all in for less than a full raise does not reopen the
+	// betting, until all ins together come to one.
+	r := NewRound(NoLimit, 10, 0)
+	r.Raise(100)
+	bettor := Player{Stack: 900, Bet: 100, Acted: true, FacedBet: 100}
+	r.Raise(150)
+	if r.LastRaiseSize != 100 || r.Raises != 1 || r.Reopened(bettor) {
+		t.Errorf("No-limit all in to 150 over 100 left %+v but expected no reopening", r)
+	}
+	if options := r.Options(bettor, 250, true); fmt.Sprint(options) != "{false 50 0 0}" {
+		t.Errorf("Options for the bettor == %v but expected a call of 50 only", options)
+	}
+	r.Raise(210)
+	if !r.Reopened(bettor) {
+		t.Errorf("No-limit all ins to 210 over 100 left %+v but expected the betting reopened", r)
+	}
+	r.Raise(400)
+	if r.LastRaiseSize != 190 || r.Raises != 2 || r.FullBet != 400 {
+		t.Errorf("No-limit raise to 400 left %+v but expected a full raise of 190", r)
+	}
+
+	// Fixed-limit: half a bet or more counts as a full raise.
+	r = NewRound(FixedLimit, 10, 0)
+	r.Raise(10)
+	bettor = Player{Stack: 90, Bet: 10, Acted: true, FacedBet: 10}
+	r.Raise(14)
+	if r.Raises != 1 || r.FullBet != 10 || r.Reopened(bettor) {
+		t.Errorf("Fixed-limit all in to 14 over 10 left %+v but expected no reopening", r)
+	}
+	if options := r.Options(Player{Stack: 100}, 24, true); fmt.Sprint(options) != "{false 14 20 20}" {
+		t.Errorf("Options after a short all in == %v but expected a raise to 20", options)
+	}
+	r.Raise(15)
+	if r.Raises != 2 || r.FullBet != 15 || !r.Reopened(bettor) {
+		t.Errorf("Fixed-limit all in to 15 over 10 left %+v but expected a full raise", r)
+	}
+
+	// Completing a bring-in is the first full bet.
+	r = Round{Limit: FixedLimit, BetSize: 10, CurrentBet: 3}
+	r.Raise(10)
+	if r.Raises != 1 || r.FullBet != 10 {
+		t.Errorf("Completing a bring-in left %+v but expected one full bet", r)
+	}
+}
@@ -0,0 +1,249 @@
+package betting // github.com/sildani/poker-hands-go/betting
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sildani/poker-hands-go/pot"
+)
+
+const Fold = "fold"
+const Check = "check"
+const Call = "call"
+const Bet = "bet"
+const Raise = "raise"
+
+// Events that are not player decisions.
+const Return = "return"
+const Win = "win"
+
+// Chips are a player's chips in a hand: the stack behind, the bet on this
+// street and the total put in over the hand.
+type Chips struct {
+	Stack  int
+	Bet    int
+	Total  int
+	Folded bool
+	AllIn  bool
+}
+
+// Event is something that happened in the hand. For a call Amount is what
+// was put in; for a bet or raise it is the total bet on this street.
+type Event struct {
+	Seat   int
+	Street int
+	Type   string
+	Amount int
+}
+
+// Table is the betting side of a hand, shared by the hold'em, draw and
+// stud engines: the chips of each seat, the round on the current street,
+// whose turn it is and what has happened. The engines deal the cards and
+// decide when a street ends and who wins.
+//
+// Acted and FacedBet are, for each seat, whether it has acted on this
+// street and the current bet right after it last did. Dead is chips in the
+// pot that no seat put in this hand, which go to the main pot.
+type Table struct {
+	Seats    []*Chips
+	Dead     int
+	Round    Round
+	Street   int
+	ToAct    int
+	Acted    []bool
+	FacedBet []int
+	Events   []Event
+	Winnings []int
+}
+
+func NewTable(seats []*Chips) Table {
+	return Table{
+		Seats:    seats,
+		Acted:    make([]bool, len(seats)),
+		FacedBet: make([]int, len(seats)),
+		Events:   []Event{},
+		Winnings: make([]int, len(seats)),
+	}
+}
+
+func (t *Table) Pot() int {
+	pot := t.Dead
+	for _, c := range t.Seats {
+		pot += c.Total
+	}
+	return pot
+}
+
+// Options are the betting options for the seat to act.
+func (t *Table) Options() Options {
+	c := t.Seats[t.ToAct]
+	player := Player{Stack: c.Stack, Bet: c.Bet, Acted: t.Acted[t.ToAct], FacedBet: t.FacedBet[t.ToAct]}
+	return t.Round.Options(player, t.Pot(), t.OthersCanAct(t.ToAct))
+}
+
+// Act folds, checks, calls, bets or raises to amount for the seat to act,
+// named name in errors, if options allow it. It does not move the turn on.
+func (t *Table) Act(name string, kind string, amount int, options Options) error {
+	seat := t.ToAct
+	c := t.Seats[seat]
+
+	switch kind {
+	case Fold:
+		c.Folded = true
+		t.Events = append(t.Events, Event{Seat: seat, Street: t.Street, Type: Fold})
+	case Check:
+		if !options.Check {
+			return fmt.Errorf("Invalid action: %s must call %d or fold", name, options.Call)
+		}
+		t.Events = append(t.Events, Event{Seat: seat, Street: t.Street, Type: Check})
+	case Call:
+		if options.Call == 0 {
+			return errors.New("Invalid action: there is no bet to call")
+		}
+		t.Post(seat, Call, options.Call)
+	case Bet, Raise:
+		if kind == Bet && t.Round.CurrentBet > 0 {
+			return errors.New("Invalid action: there is already a bet, so raise")
+		}
+		if kind == Raise && t.Round.CurrentBet == 0 {
+			return errors.New("Invalid action: there is no bet to raise, so bet")
+		}
+		if options.MinRaise == 0 {
+			return fmt.Errorf("Invalid action: %s may not %s", name, kind)
+		}
+		if amount < options.MinRaise || amount > options.MaxRaise {
+			return fmt.Errorf("Invalid action: %s must be from %d to %d", kind, options.MinRaise, options.MaxRaise)
+		}
+		t.Round.Raise(amount)
+		t.Post(seat, kind, amount-c.Bet)
+		t.Events[len(t.Events)-1].Amount = amount
+	default:
+		return fmt.Errorf("Invalid action: unknown action %q", kind)
+	}
+
+	t.Acted[seat] = true
+	t.FacedBet[seat] = t.Round.CurrentBet
+	return nil
+}
+
+// Post puts up to amount from seat's stack in as kind, such as an ante,
+// blind or call, going all in when it takes the rest.
+func (t *Table) Post(seat int, kind string, amount int) {
+	c := t.Seats[seat]
+	amount = min(amount, c.Stack)
+	c.Stack -= amount
+	c.Bet += amount
+	c.Total += amount
+	if c.Stack == 0 {
+		c.AllIn = true
+	}
+	t.Events = append(t.Events, Event{Seat: seat, Street: t.Street, Type: kind, Amount: amount})
+}
+
+// Next moves the turn to the first seat after ToAct that needs to act, and
+// is false when nobody does and the betting on the street is done.
+func (t *Table) Next() bool {
+	for i := 1; i <= len(t.Seats); i++ {
+		seat := (t.ToAct + i) % len(t.Seats)
+		if t.NeedsToAct(seat) {
+			t.ToAct = seat
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Table) NeedsToAct(seat int) bool {
+	c := t.Seats[seat]
+	if c.Folded || c.AllIn {
+		return false
+	}
+	if c.Bet < t.Round.CurrentBet {
+		return true
+	}
+	// A lone player who is not all in has no one left to bet against.
+	return !t.Acted[seat] && t.OthersCanAct(seat)
+}
+
+// OthersCanAct is false when every other player still in is all in.
+func (t *Table) OthersCanAct(seat int) bool {
+	for i, c := range t.Seats {
+		if i != seat && !c.Folded && !c.AllIn {
+			return true
+		}
+	}
+	return false
+}
+
+// Contenders are the players who have not folded.
+func (t *Table) Contenders() int {
+	contenders := 0
+	for _, c := range t.Seats {
+		if !c.Folded {
+			contenders++
+		}
+	}
+	return contenders
+}
+
+// NextStreet moves on to the next street with round for its betting.
+func (t *Table) NextStreet(round Round) {
+	t.Street++
+	for i, c := range t.Seats {
+		c.Bet = 0
+		t.Acted[i] = false
+		t.FacedBet[i] = 0
+	}
+	t.Round = round
+}
+
+// ReturnUncalled gives back the part of the biggest bet nobody matched.
+func (t *Table) ReturnUncalled() {
+	biggest, second := -1, 0
+	for i, c := range t.Seats {
+		if biggest == -1 || c.Bet > t.Seats[biggest].Bet {
+			if biggest != -1 {
+				second = t.Seats[biggest].Bet
+			}
+			biggest = i
+		} else if c.Bet > second {
+			second = c.Bet
+		}
+	}
+
+	c := t.Seats[biggest]
+	if uncalled := c.Bet - second; uncalled > 0 {
+		c.Bet -= uncalled
+		c.Total -= uncalled
+		c.Stack += uncalled
+		c.AllIn = false
+		t.Events = append(t.Events, Event{Seat: biggest, Street: t.Street, Type: Return, Amount: uncalled})
+	}
+}
+
+// Settle ends the hand on the showdown street, building the main and side
+// pots and paying each as award splits it between the seats.
+func (t *Table) Settle(showdown int, award func(p pot.Pot) []int) []pot.Pot {
+	t.Street = showdown
+
+	contributions := []int{}
+	folded := []bool{}
+	for _, c := range t.Seats {
+		contributions = append(contributions, c.Total)
+		folded = append(folded, c.Folded)
+	}
+
+	pots := pot.Build(contributions, folded)
+	if len(pots) > 0 {
+		pots[0].Amount += t.Dead
+	}
+	for _, p := range pots {
+		for seat, amount := range award(p) {
+			if amount > 0 {
+				t.Winnings[seat] += amount
+				t.Seats[seat].Stack += amount
+				t.Events = append(t.Events, Event{Seat: seat, Street: showdown, Type: Win, Amount: amount})
+			}
+		}
+	}
+	return pots
+}
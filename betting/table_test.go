@@ -0,0 +1,77 @@
+package betting // github.com/sildani/poker-hands-go/betting
+
+import (
+	"fmt"
+	"github.com/sildani/poker-hands-go/pot"
+	"testing"
+)
+
+func table(stacks ...int) Table {
+	seats := []*Chips{}
+	for _, stack := range stacks {
+		seats = append(seats, &Chips{Stack: stack})
+	}
+	t := NewTable(seats)
+	t.Round = NewRound(NoLimit, 2, 0)
+	return t
+}
+
+func TestTableAct(t *testing.T) {
+	tb := table(100, 100, 30)
+
+	tests := []struct {
+		kind        string
+		amount      int
+		expectedErr string
+	}{
+		{Call, 0, "Invalid action: there is no bet to call"},
+		{Raise, 4, "Invalid action: there is no bet to raise, so bet"},
+		{Bet, 1, "Invalid action: bet must be from 2 to 100"},
+		{"limp", 0, `Invalid action: unknown action "limp"`},
+	}
+	for _, test := range tests {
+		if err := tb.Act("Alice", test.kind, test.amount, tb.Options()); err == nil || err.Error() != test.expectedErr {
+			t.Errorf("Act(%s %d) err == %v but expected %q", test.kind, test.amount, err, test.expectedErr)
+		}
+	}
+
+	tb.Act("Alice", Bet, 10, tb.Options())
+	if !tb.Next() || tb.ToAct != 1 {
+		t.Fatalf("Next() after a bet moved to seat %d but expected seat 1", tb.ToAct)
+	}
+	if err := tb.Act("Bob", Check, 0, tb.Options()); err == nil || err.Error() != "Invalid action: Bob must call 10 or fold" {
+		t.Errorf("Act(check) facing a bet err == %v but expected a call or fold", err)
+	}
+	tb.Act("Bob", Fold, 0, tb.Options())
+	tb.Next()
+	tb.Act("Carol", Call, 0, tb.Options())
+	if tb.Next() || tb.Pot() != 20 || tb.Seats[2].Stack != 20 {
+		t.Errorf("Next() == true or pot %d but expected the betting done with 20 in", tb.Pot())
+	}
+	if fmt.Sprint(tb.Events) != "[{0 0 bet 10} {1 0 fold 0} {2 0 call 10}]" {
+		t.Errorf("Events == %v but expected a bet, a fold and a call", tb.Events)
+	}
+}
+
+func TestTableReturnUncalledAndSettle(t *testing.T) {
+	tb := table(100, 30, 100)
+	tb.ToAct = 0
+	tb.Act("Alice", Bet, 80, tb.Options())
+	tb.Next()
+	tb.Act("Bob", Call, 0, tb.Options())
+	tb.Next()
+	tb.Act("Carol", Fold, 0, tb.Options())
+
+	tb.ReturnUncalled()
+	if tb.Seats[0].Stack != 70 || tb.Seats[0].Total != 30 {
+		t.Errorf("after ReturnUncalled seat 0 has %d behind and %d in but expected 70 and 30", tb.Seats[0].Stack, tb.Seats[0].Total)
+	}
+
+	pots := tb.Settle(4, func(p pot.Pot) []int { return []int{0, p.Amount, 0} })
+	if fmt.Sprint(pots) != "[{60 [0 1]}]" || fmt.Sprint(tb.Winnings) != "[0 60 0]" || tb.Seats[1].Stack != 60 {
+		t.Errorf("Settle == %v with winnings %v but expected seat 1 to win the 60 pot", pots, tb.Winnings)
+	}
+	if last := tb.Events[len(tb.Events)-1]; fmt.Sprint(last) != "{1 4 win 60}" || tb.Street != 4 {
+		t.Errorf("last event == %v on street %d but expected seat 1 to win 60 at showdown", last, tb.Street)
+	}
+}
@@ -0,0 +1,175 @@
+package board // github.com/sildani/poker-hands-go/board
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sildani/poker-hands-go/evaluator"
+	"github.com/sildani/poker-hands-go/parser"
+	"strings"
+)
+
+const Rainbow = "rainbow"
+const TwoTone = "two-tone"
+const Monotone = "monotone"
+
+const Unpaired = "unpaired"
+const Paired = "paired"
+const TwoPaired = "two pair"
+const Trips = "trips"
+const FullHouse = "full house"
+const Quads = "quads"
+
+type Texture struct {
+	Cards            []string
+	Pairing          string
+	Suits            string
+	Connectedness    int
+	StraightPossible bool
+	FlushPossible    bool
+	Nuts             evaluator.Evaluation
+	NutHoldings      [][]string
+}
+
+// Analyse describes a board of three to five community cards. Connectedness
+// is the most distinct board values that fit in one straight, so a straight
+// is possible once it reaches three.
+func Analyse(board string) (Texture, error) {
+	cards, err := parser.ParseCards(board)
+	if err != nil {
+		return Texture{}, fmt.Errorf("Invalid board: %v", strings.TrimPrefix(err.Error(), "Invalid cards: "))
+	}
+	if len(cards) < 3 || len(cards) > 5 {
+		return Texture{}, errors.New("Invalid board: must have three to five cards")
+	}
+
+	suits := make(map[string]int)
+	values := make(map[int]int)
+	for _, card := range cards {
+		value, _ := parser.ParseCardValue(card[:1])
+		suits[card[1:]] += 1
+		values[value] += 1
+	}
+
+	connectedness := mostInStraight(values)
+	nuts, nutHoldings := findNuts(cards)
+
+	return Texture{
+		Cards:            cards,
+		Pairing:          pairing(values),
+		Suits:            suitTexture(suits, len(cards)),
+		Connectedness:    connectedness,
+		StraightPossible: connectedness >= 3,
+		FlushPossible:    mostOfOneSuit(suits) >= 3,
+		Nuts:             nuts,
+		NutHoldings:      nutHoldings,
+	}, nil
+}
+
+// Holdings returns every two card holding that does not use a known card.
+func Holdings(known []string) [][]string {
+	unseen := Unseen(known)
+
+	holdings := [][]string{}
+	for i := 0; i < len(unseen); i++ {
+		for j := i + 1; j < len(unseen); j++ {
+			holdings = append(holdings, []string{unseen[i], unseen[j]})
+		}
+	}
+	return holdings
+}
+
+// Unseen returns the cards of the deck that are not known.
+func Unseen(known []string) []string {
+	isKnown := make(map[string]bool)
+	for _, card := range known {
+		isKnown[card] = true
+	}
+
+	unseen := []string{}
+	for _, card := range parser.Cards() {
+		if !isKnown[card] {
+			unseen = append(unseen, card)
+		}
+	}
+	return unseen
+}
+
+func findNuts(cards []string) (evaluator.Evaluation, [][]string) {
+	nuts := evaluator.Evaluation{}
+	nutHoldings := [][]string{}
+
+	for _, holding := range Holdings(cards) {
+		evaluation := evaluator.EvaluateBestHand(append(append([]string{}, cards...), holding...))
+		switch evaluator.Compare(evaluation, nuts) {
+		case 1:
+			nuts = evaluation
+			nutHoldings = [][]string{holding}
+		case 0:
+			nutHoldings = append(nutHoldings, holding)
+		}
+	}
+
+	return nuts, nutHoldings
+}
+
+func pairing(values map[int]int) string {
+	pairs := 0
+	trips := 0
+	for _, count := range values {
+		switch count {
+		case 4:
+			return Quads
+		case 3:
+			trips++
+		case 2:
+			pairs++
+		}
+	}
+
+	if trips == 1 && pairs == 1 {
+		return FullHouse
+	} else if trips == 1 {
+		return Trips
+	} else if pairs == 2 {
+		return TwoPaired
+	} else if pairs == 1 {
+		return Paired
+	}
+	return Unpaired
+}
+
+func suitTexture(suits map[string]int, cards int) string {
+	if len(suits) == 1 {
+		return Monotone
+	} else if len(suits) == cards {
+		return Rainbow
+	}
+	return TwoTone
+}
+
+func mostOfOneSuit(suits map[string]int) int {
+	most := 0
+	for _, count := range suits {
+		if count > most {
+			most = count
+		}
+	}
+	return most
+}
+
+func mostInStraight(values map[int]int) int {
+	most := 0
+	// A straight can start anywhere from the ace (as one) to the ten.
+	for low := 1; low <= 10; low++ {
+		count := 0
+		for value := low; value < low+5; value++ {
+			if values[value] > 0 || (value == 1 && values[14] > 0) {
+				count++
+			}
+		}
+		if count > most {
+			most = count
+		}
+	}
+	return most
+}
@@ -0,0 +1,110 @@
+package board // github.com/sildani/poker-hands-go/board
+
+import (
+	"github.com/sildani/poker-hands-go/evaluator"
+	"testing"
+)
+
+func TestAnalyseInvalidBoard(t *testing.T) {
+	tests := []struct {
+		board       string
+		expectedErr string
+	}{
+		{"", "Invalid board: must have at least one card"},
+		{"AH KH", "Invalid board: must have three to five cards"},
+		{"AH KH QH JH TH 9H", "Invalid board: must have three to five cards"},
+		{"AH KH QP", "Invalid board: contains invalid card"},
+		{"AH KH AH", "Invalid board: contains duplicate card"},
+	}
+
+	for _, test := range tests {
+		_, err := Analyse(test.board)
+		if err == nil {
+			t.Errorf("Analyse(%q) err == nil but expected %q", test.board, test.expectedErr)
+		} else if err.Error() != test.expectedErr {
+			t.Errorf("Analyse(%q) err == %q but expected %q", test.board, err, test.expectedErr)
+		}
+	}
+}
+
+func TestAnalyse(t *testing.T) {
+	tests := []struct {
+		board                    string
+		expectedPairing          string
+		expectedSuits            string
+		expectedConnectedness    int
+		expectedStraightPossible bool
+		expectedFlushPossible    bool
+		expectedNutCategory      int
+		expectedNutHoldings      int
+	}{
+		{"2C 7D KH", Unpaired, Rainbow, 1, false, false, evaluator.ThreeOfAKind, 3},
+		{"2C 7C KH", Unpaired, TwoTone, 1, false, false, evaluator.ThreeOfAKind, 3},
+		{"9H TH JH", Unpaired, Monotone, 3, true, true, evaluator.StraightFlush, 1},
+		{"AS 2D 3C", Unpaired, Rainbow, 3, true, false, evaluator.Straight, 16},
+		{"8S 8D 3C", Paired, Rainbow, 1, false, false, evaluator.FourOfAKind, 1},
+		{"8S 8D 8C", Trips, Rainbow, 1, false, false, evaluator.FourOfAKind, 4},
+		{"8S 8D 3C 3H", TwoPaired, Rainbow, 1, false, false, evaluator.FourOfAKind, 1},
+		{"2C 7C KC 4D JH", Unpaired, TwoTone, 2, false, true, evaluator.Flush, 1},
+		{"5D 6C 8C 9H KS", Unpaired, TwoTone, 4, true, false, evaluator.Straight, 16},
+	}
+
+	for _, test := range tests {
+		texture, err := Analyse(test.board)
+		if err != nil {
+			t.Errorf("Analyse(%q) err == %q but expected nil", test.board, err)
+			continue
+		}
+		if texture.Pairing != test.expectedPairing {
+			t.Errorf("Analyse(%q).Pairing == %q but expected %q", test.board, texture.Pairing, test.expectedPairing)
+		}
+		if texture.Suits != test.expectedSuits {
+			t.Errorf("Analyse(%q).Suits == %q but expected %q", test.board, texture.Suits, test.expectedSuits)
+		}
+		if texture.Connectedness != test.expectedConnectedness {
+			t.Errorf("Analyse(%q).Connectedness == %d but expected %d",
+				test.board, texture.Connectedness, test.expectedConnectedness)
+		}
+		if texture.StraightPossible != test.expectedStraightPossible {
+			t.Errorf("Analyse(%q).StraightPossible == %t but expected %t",
+				test.board, texture.StraightPossible, test.expectedStraightPossible)
+		}
+		if texture.FlushPossible != test.expectedFlushPossible {
+			t.Errorf("Analyse(%q).FlushPossible == %t but expected %t",
+				test.board, texture.FlushPossible, test.expectedFlushPossible)
+		}
+		if texture.Nuts.Category() != test.expectedNutCategory {
+			t.Errorf("Analyse(%q).Nuts.Category() == %d but expected %d",
+				test.board, texture.Nuts.Category(), test.expectedNutCategory)
+		}
+		if len(texture.NutHoldings) != test.expectedNutHoldings {
+			t.Errorf("len(Analyse(%q).NutHoldings) == %d but expected %d",
+				test.board, len(texture.NutHoldings), test.expectedNutHoldings)
+		}
+	}
+}
+
+func TestHoldings(t *testing.T) {
+	tests := []struct {
+		known            []string
+		expectedHoldings int
+	}{
+		{[]string{}, 1326},
+		{[]string{"AH", "KH", "QH"}, 1176},
+		{[]string{"AH", "KH", "QH", "JH", "TH"}, 1081},
+	}
+
+	for _, test := range tests {
+		holdings := Holdings(test.known)
+		if len(holdings) != test.expectedHoldings {
+			t.Errorf("len(Holdings(%v)) == %d but expected %d", test.known, len(holdings), test.expectedHoldings)
+		}
+		for _, holding := range holdings {
+			for _, card := range test.known {
+				if holding[0] == card || holding[1] == card {
+					t.Errorf("Holdings(%v) contains known card %q", test.known, card)
+				}
+			}
+		}
+	}
+}
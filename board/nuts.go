@@ -0,0 +1,108 @@
+package board // github.com/sildani/poker-hands-go/board
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sildani/poker-hands-go/evaluator"
+	"github.com/sildani/poker-hands-go/parser"
+	"sort"
+	"strings"
+)
+
+type Holding struct {
+	Cards      []string
+	Evaluation evaluator.Evaluation
+	Rank       int
+}
+
+// Standing is where a hand stands against every holding an opponent could
+// have. Rank 1 is the nuts; holdings that tie share a rank.
+type Standing struct {
+	Beats    int
+	Ties     int
+	Loses    int
+	Rank     int
+	Holdings []Holding
+}
+
+func (s Standing) Describe() string {
+	switch s.Rank {
+	case 1:
+		return "the nuts"
+	case 2:
+		return "second nuts"
+	case 3:
+		return "third nuts"
+	}
+	suffix := "th"
+	if s.Rank%100 < 11 || s.Rank%100 > 13 {
+		switch s.Rank % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s nuts", s.Rank, suffix)
+}
+
+// Rank compares two hole cards against every other holding on a board of
+// three to five cards.
+func Rank(board string, hole string) (Standing, error) {
+	boardCards, err := parser.ParseCards(board)
+	if err != nil {
+		return Standing{}, fmt.Errorf("Invalid board: %v", strings.TrimPrefix(err.Error(), "Invalid cards: "))
+	}
+	if len(boardCards) < 3 || len(boardCards) > 5 {
+		return Standing{}, errors.New("Invalid board: must have three to five cards")
+	}
+
+	holeCards, err := parser.ParseCards(board + " " + hole)
+	if err != nil {
+		return Standing{}, fmt.Errorf("Invalid hole cards: %v", strings.TrimPrefix(err.Error(), "Invalid cards: "))
+	}
+	holeCards = holeCards[len(boardCards):]
+	if len(holeCards) != 2 {
+		return Standing{}, errors.New("Invalid hole cards: must have two cards")
+	}
+
+	hand := evaluator.EvaluateBestHand(append(append([]string{}, boardCards...), holeCards...))
+
+	holdings := []Holding{}
+	for _, cards := range Holdings(append(append([]string{}, boardCards...), holeCards...)) {
+		holdings = append(holdings, Holding{
+			Cards:      cards,
+			Evaluation: evaluator.EvaluateBestHand(append(append([]string{}, boardCards...), cards...)),
+		})
+	}
+	sort.SliceStable(holdings, func(i, j int) bool {
+		return evaluator.Compare(holdings[i].Evaluation, holdings[j].Evaluation) > 0
+	})
+
+	standing := Standing{Rank: 1, Holdings: holdings}
+	for i := range holdings {
+		holdings[i].Rank = 1
+		if i > 0 {
+			holdings[i].Rank = holdings[i-1].Rank
+			if evaluator.Compare(holdings[i].Evaluation, holdings[i-1].Evaluation) < 0 {
+				holdings[i].Rank++
+			}
+		}
+
+		switch evaluator.Compare(hand, holdings[i].Evaluation) {
+		case 1:
+			standing.Beats++
+		case 0:
+			standing.Ties++
+		case -1:
+			standing.Loses++
+			if i == 0 || holdings[i].Rank != holdings[i-1].Rank {
+				standing.Rank++
+			}
+		}
+	}
+
+	return standing, nil
+}
@@ -0,0 +1,121 @@
+package board // github.com/sildani/poker-hands-go/board
+
+import (
+	"github.com/sildani/poker-hands-go/evaluator"
+	"testing"
+)
+
+func TestRankInvalid(t *testing.T) {
+	tests := []struct {
+		board       string
+		hole        string
+		expectedErr string
+	}{
+		{"AH KH", "2C 2D", "Invalid board: must have three to five cards"},
+		{"AH KH QP", "2C 2D", "Invalid board: contains invalid card"},
+		{"AH KH QH", "2C", "Invalid hole cards: must have two cards"},
+		{"AH KH QH", "2C 2D 2S", "Invalid hole cards: must have two cards"},
+		{"AH KH QH", "2C AH", "Invalid hole cards: contains duplicate card"},
+		{"AH KH QH", "2C 2P", "Invalid hole cards: contains invalid card"},
+	}
+
+	for _, test := range tests {
+		_, err := Rank(test.board, test.hole)
+		if err == nil {
+			t.Errorf("Rank(%q, %q) err == nil but expected %q", test.board, test.hole, test.expectedErr)
+		} else if err.Error() != test.expectedErr {
+			t.Errorf("Rank(%q, %q) err == %q but expected %q", test.board, test.hole, err, test.expectedErr)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	tests := []struct {
+		board            string
+		hole             string
+		expectedBeats    int
+		expectedTies     int
+		expectedLoses    int
+		expectedRank     int
+		expectedDescribe string
+	}{
+		{"2C 7D KH", "KC KD", 1081, 0, 0, 1, "the nuts"},
+		{"2C 7D KH", "7C 7S", 1078, 0, 3, 2, "second nuts"},
+		{"2C 7D KH", "2D 2H", 1075, 0, 6, 3, "third nuts"},
+		{"AS 2D 3C 9H JH", "4H 5H", 981, 9, 0, 1, "the nuts"},
+	}
+
+	for _, test := range tests {
+		standing, err := Rank(test.board, test.hole)
+		if err != nil {
+			t.Errorf("Rank(%q, %q) err == %q but expected nil", test.board, test.hole, err)
+			continue
+		}
+		if standing.Beats != test.expectedBeats || standing.Ties != test.expectedTies || standing.Loses != test.expectedLoses {
+			t.Errorf("Rank(%q, %q) beats/ties/loses == %d/%d/%d but expected %d/%d/%d",
+				test.board, test.hole, standing.Beats, standing.Ties, standing.Loses,
+				test.expectedBeats, test.expectedTies, test.expectedLoses)
+		}
+		if standing.Rank != test.expectedRank {
+			t.Errorf("Rank(%q, %q).Rank == %d but expected %d", test.board, test.hole, standing.Rank, test.expectedRank)
+		}
+		if standing.Describe() != test.expectedDescribe {
+			t.Errorf("Rank(%q, %q).Describe() == %q but expected %q",
+				test.board, test.hole, standing.Describe(), test.expectedDescribe)
+		}
+		if len(standing.Holdings) != standing.Beats+standing.Ties+standing.Loses {
+			t.Errorf("len(Rank(%q, %q).Holdings) == %d but expected %d", test.board, test.hole,
+				len(standing.Holdings), standing.Beats+standing.Ties+standing.Loses)
+		}
+	}
+}
+
+func TestStandingDescribe(t *testing.T) {
+	tests := []struct {
+		rank     int
+		expected string
+	}{
+		{1, "the nuts"},
+		{2, "second nuts"},
+		{3, "third nuts"},
+		{4, "4th nuts"},
+		{11, "11th nuts"},
+		{12, "12th nuts"},
+		{13, "13th nuts"},
+		{21, "21st nuts"},
+		{22, "22nd nuts"},
+		{23, "23rd nuts"},
+		{111, "111th nuts"},
+		{101, "101st nuts"},
+	}
+
+	for _, test := range tests {
+		if described := (Standing{Rank: test.rank}).Describe(); described != test.expected {
+			t.Errorf("Standing{Rank: %d}.Describe() == %q but expected %q", test.rank, described, test.expected)
+		}
+	}
+}
+
+func TestRankHoldingsAreSorted(t *testing.T) {
+	standing, err := Rank("9H TH JH", "2C 2D")
+	if err != nil {
+		t.Fatalf("Rank err == %q but expected nil", err)
+	}
+
+	first := standing.Holdings[0]
+	if first.Rank != 1 || first.Evaluation.Category() != evaluator.StraightFlush {
+		t.Errorf("Holdings[0] == %v ranked %d but expected the king high straight flush ranked 1",
+			first.Cards, first.Rank)
+	}
+
+	for i := 1; i < len(standing.Holdings); i++ {
+		previous := standing.Holdings[i-1]
+		holding := standing.Holdings[i]
+		if evaluator.Compare(previous.Evaluation, holding.Evaluation) < 0 {
+			t.Errorf("Holdings[%d] %v beats Holdings[%d] %v", i, holding.Cards, i-1, previous.Cards)
+		}
+		if holding.Rank < previous.Rank || holding.Rank > previous.Rank+1 {
+			t.Errorf("Holdings[%d].Rank == %d after rank %d", i, holding.Rank, previous.Rank)
+		}
+	}
+}
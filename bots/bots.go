@@ -0,0 +1,112 @@
+package bots // github.com/sildani/poker-hands-go/bots
+
+import (
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/evaluator"
+	"github.com/sildani/poker-hands-go/holdem"
+	"github.com/sildani/poker-hands-go/preflop"
+	"github.com/sildani/poker-hands-go/strength"
+)
+
+// Random picks uniformly from folding (when there is a bet), checking or
+// calling, and betting or raising, then a uniform amount for a bet or
+// raise.
+type Random struct {
+	rng deck.RNG
+}
+
+func NewRandom(rng deck.RNG) *Random {
+	return &Random{rng: rng}
+}
+
+func (b *Random) Act(view holdem.View) holdem.Action {
+	choices := []string{holdem.Check}
+	if !view.Options.Check {
+		choices = []string{holdem.Fold, holdem.Call}
+	}
+	if view.Options.MinRaise > 0 {
+		choices = append(choices, holdem.Raise)
+	}
+
+	switch choice := choices[b.rng.Intn(len(choices))]; choice {
+	case holdem.Raise:
+		amount := view.Options.MinRaise + b.rng.Intn(view.Options.MaxRaise-view.Options.MinRaise+1)
+		return raise(view, amount)
+	default:
+		return holdem.Action{Type: choice}
+	}
+}
+
+// CallingStation checks or calls whatever it holds.
+type CallingStation struct{}
+
+func (CallingStation) Act(view holdem.View) holdem.Action {
+	if view.Options.Check {
+		return holdem.Action{Type: holdem.Check}
+	}
+	return holdem.Action{Type: holdem.Call}
+}
+
+// HandStrength plays by rules on its equity against the players still in:
+// preflop its class's equity against random hands, sampled, and after the
+// flop strength.HandStrength. With the nuts in sight, a straight or better
+// or equity over Strong, it bets or raises the pot; over Good, half the
+// pot; otherwise it calls when the pot odds are right and checks or folds
+// when they are not.
+type HandStrength struct {
+	Strong  float64
+	Good    float64
+	samples int
+	rng     deck.RNG
+}
+
+// NewHandStrength returns the bot with thresholds of 0.8 and 0.6, sampling
+// preflop equities from samples boards.
+func NewHandStrength(samples int, rng deck.RNG) *HandStrength {
+	return &HandStrength{Strong: 0.8, Good: 0.6, samples: samples, rng: rng}
+}
+
+func (b *HandStrength) Act(view holdem.View) holdem.Action {
+	opponents := -1
+	for _, p := range view.Players {
+		if !p.Folded {
+			opponents++
+		}
+	}
+
+	var equity float64
+	category := evaluator.HighCard
+	if len(view.Board) == 0 {
+		equity = preflop.EquityVsRandom(preflop.ClassOf(view.Hole), opponents, b.samples, b.rng)
+	} else {
+		equity, _ = strength.HandStrength(view.Hole, view.Board, opponents)
+		category = evaluator.ScoreCategory(evaluator.Score(append(append([]string{}, view.Board...), view.Hole...)))
+	}
+
+	call := view.Options.Call
+	if view.Options.MinRaise > 0 {
+		if equity > b.Strong || category >= evaluator.Straight {
+			return raise(view, view.CurrentBet+call+view.Pot)
+		}
+		if equity > b.Good {
+			return raise(view, view.CurrentBet+(call+view.Pot)/2)
+		}
+	}
+
+	if view.Options.Check {
+		return holdem.Action{Type: holdem.Check}
+	}
+	if equity >= float64(call)/float64(view.Pot+call) {
+		return holdem.Action{Type: holdem.Call}
+	}
+	return holdem.Action{Type: holdem.Fold}
+}
+
+// raise bets or raises to amount, kept within the options.
+func raise(view holdem.View, amount int) holdem.Action {
+	amount = max(min(amount, view.Options.MaxRaise), view.Options.MinRaise)
+	if view.CurrentBet == 0 {
+		return holdem.Action{Type: holdem.Bet, Amount: amount}
+	}
+	return holdem.Action{Type: holdem.Raise, Amount: amount}
+}
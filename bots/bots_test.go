@@ -0,0 +1,42 @@
+package bots // github.com/sildani/poker-hands-go/bots
+
+import (
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/holdem"
+	"github.com/sildani/poker-hands-go/sim"
+	"testing"
+)
+
+func match(hands int, players ...sim.Player) ([]sim.Result, error) {
+	config := sim.Config{Game: holdem.Config{SmallBlind: 1, BigBlind: 2}, Stack: 200, Hands: hands, Duplicate: true, Seed: 1}
+	return sim.Run(config, players)
+}
+
+func TestBotsPlayLegally(t *testing.T) {
+	players := []sim.Player{
+		NewRandom(deck.NewSeededRNG(1)),
+		CallingStation{},
+		NewHandStrength(50, deck.NewSeededRNG(2)),
+		NewRandom(deck.NewSeededRNG(3)),
+	}
+	if _, err := match(200, players...); err != nil {
+		t.Errorf("match err == %q but expected every action to be legal", err)
+	}
+}
+
+func TestBotsAreDeterministic(t *testing.T) {
+	first, _ := match(100, NewRandom(deck.NewSeededRNG(7)), NewHandStrength(50, deck.NewSeededRNG(8)))
+	second, _ := match(100, NewRandom(deck.NewSeededRNG(7)), NewHandStrength(50, deck.NewSeededRNG(8)))
+	if first[0] != second[0] || first[1] != second[1] {
+		t.Errorf("matches with the same seeds came out %+v and %+v", first, second)
+	}
+}
+
+func TestHandStrengthWins(t *testing.T) {
+	for _, opponent := range []sim.Player{NewRandom(deck.NewSeededRNG(1)), CallingStation{}} {
+		results, err := match(400, NewHandStrength(50, deck.NewSeededRNG(2)), opponent)
+		if err != nil || results[0].BBPer100 <= 0 {
+			t.Errorf("HandStrength against %T won %.1f bb/100 with err %v but expected it to win", opponent, results[0].BBPer100, err)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/sildani/poker-hands-go/arena"
+	"github.com/sildani/poker-hands-go/bots"
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/holdem"
+	"github.com/sildani/poker-hands-go/remote"
+	"github.com/sildani/poker-hands-go/sim"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// known are the bots the arena can enter by name.
+var known = map[string]func(seed int64) sim.Player{
+	"random":   func(seed int64) sim.Player { return bots.NewRandom(deck.NewSeededRNG(seed)) },
+	"station":  func(seed int64) sim.Player { return bots.CallingStation{} },
+	"strength": func(seed int64) sim.Player { return bots.NewHandStrength(200, deck.NewSeededRNG(seed)) },
+}
+
+// arena plays a round-robin between bots, adds the matches to a record
+// file and prints the leaderboard over every match in the record. Bots in
+// other languages enter as cmd:<program and args>, started once and spoken
+// to over their standard input and output, or as tcp:<address>, waited for
+// on that address; see the remote package for the protocol.
+func main() {
+	botsFlag := flag.String("bots", "random,station,strength", "comma separated bots: random, station, strength, cmd:<program> or tcp:<address>")
+	timeout := flag.Duration("timeout", time.Second, "how long cmd: and tcp: bots have to act before they fold")
+	hands := flag.Int("hands", 1000, "hands in each match, counting every duplicate rotation")
+	tableSize := flag.Int("table", 3, "players at the multiway tables; 2 plays heads-up only")
+	stack := flag.Int("stack", 200, "chips each player starts every hand with")
+	seed := flag.Int64("seed", 1, "seed for the first match")
+	recordFile := flag.String("record", "arena.jsonl", "file the matches are added to, one JSON match a line")
+	flag.Parse()
+
+	if err := run(*botsFlag, *timeout, *hands, *tableSize, *stack, *seed, *recordFile); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+}
+
+func run(botsFlag string, timeout time.Duration, hands int, tableSize int, stack int, seed int64, recordFile string) error {
+	entrants := []arena.Entrant{}
+	remotes := []*remote.Bot{}
+	defer func() {
+		for _, bot := range remotes {
+			bot.Close()
+		}
+	}()
+	for _, name := range strings.Split(botsFlag, ",") {
+		name = strings.TrimSpace(name)
+		if bot, ok := known[name]; ok {
+			entrants = append(entrants, arena.Entrant{Name: name, New: bot})
+			continue
+		}
+
+		bot, err := connect(name, timeout)
+		if err != nil {
+			return err
+		}
+		remotes = append(remotes, bot)
+		entrants = append(entrants, arena.Entrant{Name: name, New: func(seed int64) sim.Player { return bot }})
+	}
+
+	config := arena.Config{Game: holdem.Config{SmallBlind: 1, BigBlind: 2}, Stack: stack, Hands: hands, TableSize: tableSize, Seed: seed}
+	matches, err := arena.RoundRobin(config, entrants)
+	if err != nil {
+		return err
+	}
+
+	record := []arena.Match{}
+	if file, err := os.Open(recordFile); err == nil {
+		record, err = arena.Load(file)
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+	file, err := os.OpenFile(recordFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if err := arena.Save(file, matches); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	record = append(record, matches...)
+
+	fmt.Printf("%d matches played, %d in %s\n", len(matches), len(record), recordFile)
+	for _, bot := range remotes {
+		if bot.Faults > 0 {
+			fmt.Printf("%s folded %d times for misbehaving, last: %v\n", bot.Name, bot.Faults, bot.Err)
+		}
+	}
+	fmt.Println()
+	fmt.Printf("%-4s %-10s %7s %6s %8s %8s %9s\n", "Rank", "Bot", "Elo", "+/-", "Matches", "Hands", "bb/100")
+	for i, rating := range arena.Ratings(record, deck.NewSeededRNG(seed)) {
+		fmt.Printf("%-4d %-10s %7.0f %6.0f %8d %8d %9.2f\n", i+1, rating.Name, rating.Elo, rating.Error, rating.Matches, rating.Hands, rating.BBPer100)
+	}
+	return nil
+}
+
+// connect starts or waits for a bot in another process.
+func connect(name string, timeout time.Duration) (*remote.Bot, error) {
+	if program, ok := strings.CutPrefix(name, "cmd:"); ok {
+		fields := strings.Fields(program)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("Invalid bot: %q has no program", name)
+		}
+		return remote.Command(name, timeout, fields[0], fields[1:]...)
+	}
+	if address, ok := strings.CutPrefix(name, "tcp:"); ok {
+		listener, err := net.Listen("tcp", address)
+		if err != nil {
+			return nil, err
+		}
+		defer listener.Close()
+		fmt.Printf("Waiting for %s to connect on %s\n", name, listener.Addr())
+		return remote.Accept(name, timeout, listener)
+	}
+	return nil, fmt.Errorf("Invalid bot: unknown bot %q", name)
+}
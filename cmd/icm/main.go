@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/icm"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// icm prints each player's ICM equity with the ICM and chip-chop deals for
+// a final table, e.g. -stacks 5000,3000,2000 -payouts 50,30,20. Fields
+// too big to work out exactly are sampled, repeatably with -seed.
+func main() {
+	stacksFlag := flag.String("stacks", "", "comma separated chip counts")
+	payoutsFlag := flag.String("payouts", "", "comma separated prizes from first place down")
+	seed := flag.Int64("seed", 0, "seed for a repeatable sample of big fields; 0 uses crypto/rand")
+	flag.Parse()
+
+	rng := deck.NewCryptoRNG()
+	if *seed != 0 {
+		rng = deck.NewSeededRNG(*seed)
+	}
+
+	stacks, err := parseInts(*stacksFlag)
+	if err == nil {
+		var payouts []int
+		payouts, err = parseInts(*payoutsFlag)
+		if err == nil {
+			err = run(stacks, payouts, rng)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+}
+
+func run(stacks []int, payouts []int, rng deck.RNG) error {
+	equities, err := icm.Equities(stacks, payouts, rng)
+	if err != nil {
+		return err
+	}
+	icmDeal, err := icm.DealFrom(stacks, payouts, equities)
+	if err != nil {
+		return err
+	}
+	chipChop, err := icm.ChipChop(stacks, payouts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%-8s %10s %10s %10s %10s\n", "Player", "Stack", "Equity", "ICM deal", "Chip chop")
+	for i, stack := range stacks {
+		fmt.Printf("%-8d %10d %10.2f %10d %10d\n", i+1, stack, equities[i], icmDeal[i], chipChop[i])
+	}
+	return nil
+}
+
+func parseInts(s string) ([]int, error) {
+	values := []int{}
+	for _, field := range strings.Split(s, ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid number: %q", field)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
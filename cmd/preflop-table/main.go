@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/preflop"
+	"io"
+	"os"
+)
+
+// preflop-table writes a table of sampled equities for preflop.Load, or with
+// -exact the exact heads-up showdowns shipped as preflop/exact.dat.
+func main() {
+	out := flag.String("out", "preflop.dat", "file to write the table to")
+	exact := flag.Bool("exact", false, "enumerate every board heads up instead of sampling, which takes minutes")
+	opponents := flag.Int("opponents", 9, "largest number of random opponents to compute equities against")
+	samples := flag.Int("samples", 2000, "random boards played for every equity")
+	seed := flag.Int64("seed", 1, "seed for the random boards")
+	flag.Parse()
+
+	if *opponents < 1 || *opponents > 9 {
+		fmt.Fprintln(os.Stderr, "opponents must be between 1 and 9")
+		os.Exit(2)
+	}
+
+	var table *preflop.Table
+	var write func(w io.Writer) error
+	if *exact {
+		showdowns := preflop.EnumerateShowdowns()
+		table, write = showdowns.Table(), showdowns.Write
+	} else {
+		table = preflop.Generate(*opponents, *samples, deck.NewSeededRNG(*seed))
+		write = table.Write
+	}
+
+	file, err := os.Create(*out)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := write(file); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := file.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	aces, _ := preflop.ParseClass("AA")
+	fmt.Printf("Wrote %s: AA has %.3f equity heads up against a random hand\n", *out, table.VsRandom(aces, 1))
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/sildani/poker-hands-go/preflop"
+	"github.com/sildani/poker-hands-go/pushfold"
+	"os"
+)
+
+// pushfold prints the heads-up push/fold equilibrium charts for an
+// effective stack, using the exact heads-up equities unless given a table
+// from cmd/preflop-table.
+func main() {
+	tableFile := flag.String("table", "", "preflop equity table written by cmd/preflop-table, exact equities unless set")
+	stack := flag.Float64("stack", 10, "effective stack in big blinds")
+	iterations := flag.Int("iterations", 2000, "rounds of fictitious play")
+	flag.Parse()
+
+	table := preflop.Exact()
+	if *tableFile != "" {
+		file, err := os.Open(*tableFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		table, err = preflop.Load(file)
+		file.Close()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+
+	result := pushfold.Solve(table, *stack, 0, *iterations)
+	fmt.Printf("Small blind shoves (%% of the time) at %.1f big blinds:\n%s\n", *stack, pushfold.Chart(result.Push))
+	fmt.Printf("Big blind calls (%% of the time):\n%s\n", pushfold.Chart(result.Call))
+	fmt.Printf("Small blind wins %.4f big blinds a hand; exploitability %.4f big blinds a hand\n",
+		result.Value, result.Exploitability)
+}
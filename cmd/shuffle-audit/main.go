@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/sildani/poker-hands-go/audit"
+	"github.com/sildani/poker-hands-go/deck"
+	"os"
+)
+
+func main() {
+	shuffles := flag.Int("shuffles", 1000000, "number of shuffles to run")
+	seed := flag.Int64("seed", 0, "seed for a repeatable run; 0 audits crypto/rand")
+	alpha := flag.Float64("alpha", 0.001, "fail a test when its p-value is below this")
+	flag.Parse()
+
+	rng := deck.NewCryptoRNG()
+	if *seed != 0 {
+		rng = deck.NewSeededRNG(*seed)
+	}
+
+	passed := true
+	fmt.Printf("%-22s %14s %6s %10s\n", "Test", "Chi-squared", "df", "p-value")
+	for _, result := range audit.Run(rng, *shuffles, *alpha) {
+		verdict := "pass"
+		if !result.Pass {
+			verdict = "FAIL"
+			passed = false
+		}
+		fmt.Printf("%-22s %14.2f %6d %10.4f %s\n",
+			result.Name, result.ChiSquared, result.DegreesOfFreedom, result.PValue, verdict)
+	}
+
+	if !passed {
+		os.Exit(1)
+	}
+}
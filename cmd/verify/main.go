@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/sildani/poker-hands-go/fair"
+	"io"
+	"os"
+	"strings"
+)
+
+// verify reads a provably fair hand record as JSON, from the file named as
+// its argument or from stdin, and checks it.
+func main() {
+	input := io.Reader(os.Stdin)
+	if len(os.Args) > 1 {
+		file, err := os.Open(os.Args[1])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	var record fair.HandRecord
+	if err := json.NewDecoder(input).Decode(&record); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid record: %v\n", err)
+		os.Exit(2)
+	}
+
+	cards, err := fair.Verify(record)
+	if cards != nil {
+		fmt.Printf("Deck order: %s\n", strings.Join(cards, " "))
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Println("Verified: the deck was shuffled from the committed server seed and the client seed")
+}
@@ -0,0 +1,155 @@
+package deck // github.com/sildani/poker-hands-go/deck
+
+import (
+	cryptorand "crypto/rand"
+	"fmt"
+	"github.com/sildani/poker-hands-go/parser"
+	"math/big"
+	"math/rand"
+)
+
+// RNG is the source of randomness for shuffling. *rand.Rand satisfies it.
+type RNG interface {
+	Intn(n int) int
+}
+
+type cryptoRNG struct{}
+
+func (cryptoRNG) Intn(n int) int {
+	value, err := cryptorand.Int(cryptorand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	return int(value.Int64())
+}
+
+// NewCryptoRNG returns an RNG backed by crypto/rand for live play.
+func NewCryptoRNG() RNG {
+	return cryptoRNG{}
+}
+
+// NewSeededRNG returns a repeatable RNG for tests and simulations.
+func NewSeededRNG(seed int64) RNG {
+	return rand.New(rand.NewSource(seed))
+}
+
+type Deck struct {
+	cards   []string
+	burned  []string
+	rng     RNG
+	stacked bool
+}
+
+// New returns the 52 cards in order; call Shuffle before dealing. It
+// panics without an RNG: a deck that never shuffles is NewStacked.
+func New(rng RNG) *Deck {
+	if rng == nil {
+		panic("deck: New needs an RNG; use NewStacked for a deck in a fixed order")
+	}
+	return &Deck{cards: parser.Cards(), burned: []string{}, rng: rng}
+}
+
+// NewStacked returns a deck that deals cards in order, followed by the rest
+// of the deck, for replaying recorded hands and for tests. Shuffling it does
+// nothing and sampling it takes the next cards.
+func NewStacked(cards []string) (*Deck, error) {
+	rest := &Deck{cards: parser.Cards()}
+	if err := rest.Remove(cards...); err != nil {
+		return nil, err
+	}
+	return &Deck{cards: append(append([]string{}, cards...), rest.cards...), burned: []string{}, stacked: true}, nil
+}
+
+// Shuffle puts the remaining cards in a random order (Fisher-Yates).
+func (d *Deck) Shuffle() {
+	if d.stacked {
+		return
+	}
+	for i := len(d.cards) - 1; i > 0; i-- {
+		j := d.rng.Intn(i + 1)
+		d.cards[i], d.cards[j] = d.cards[j], d.cards[i]
+	}
+}
+
+func (d *Deck) Deal(n int) ([]string, error) {
+	if n > len(d.cards) {
+		return nil, fmt.Errorf("Invalid deal: %d cards asked for but %d remain", n, len(d.cards))
+	}
+	dealt := append([]string{}, d.cards[:n]...)
+	d.cards = d.cards[n:]
+	return dealt, nil
+}
+
+func (d *Deck) Burn() error {
+	burned, err := d.Deal(1)
+	if err != nil {
+		return err
+	}
+	d.burned = append(d.burned, burned[0])
+	return nil
+}
+
+// Remove takes known cards, such as the ones a player holds in a
+// simulation, out of the deck. It removes nothing unless every card is
+// there.
+func (d *Deck) Remove(cards ...string) error {
+	inDeck := map[string]bool{}
+	for _, card := range d.cards {
+		inDeck[card] = true
+	}
+	removed := map[string]bool{}
+	for _, card := range cards {
+		if !inDeck[card] || removed[card] {
+			return fmt.Errorf("Invalid card: %s is not in the deck", card)
+		}
+		removed[card] = true
+	}
+	remaining := d.cards[:0]
+	for _, card := range d.cards {
+		if !removed[card] {
+			remaining = append(remaining, card)
+		}
+	}
+	d.cards = remaining
+	return nil
+}
+
+// Sample picks n random cards without dealing them, moving only those n
+// cards (a partial Fisher-Yates shuffle), so a simulation can remove the
+// known cards once and sample the same deck over and over. The cards are
+// only good until the next Sample, Shuffle or Deal.
+func (d *Deck) Sample(n int) ([]string, error) {
+	if n > len(d.cards) {
+		return nil, fmt.Errorf("Invalid deal: %d cards asked for but %d remain", n, len(d.cards))
+	}
+	if !d.stacked {
+		for i := 0; i < n; i++ {
+			j := i + d.rng.Intn(len(d.cards)-i)
+			d.cards[i], d.cards[j] = d.cards[j], d.cards[i]
+		}
+	}
+	return d.cards[:n], nil
+}
+
+// Reshuffle puts the burn cards and cards, such as discards in draw poker,
+// back under what is left of the deck and shuffles them in when the deck
+// runs short. The cards left are still dealt first.
+func (d *Deck) Reshuffle(cards ...string) {
+	stub := append(append([]string{}, d.burned...), cards...)
+	if !d.stacked {
+		for i := len(stub) - 1; i > 0; i-- {
+			j := d.rng.Intn(i + 1)
+			stub[i], stub[j] = stub[j], stub[i]
+		}
+	}
+	d.cards = append(d.cards, stub...)
+	d.burned = []string{}
+}
+
+func (d *Deck) Remaining() int {
+	return len(d.cards)
+}
+
+func (d *Deck) Burned() []string {
+	return append([]string{}, d.burned...)
+}
@@ -0,0 +1,200 @@
+package deck // github.com/sildani/poker-hands-go/deck
+
+import (
+	"fmt"
+	"github.com/sildani/poker-hands-go/parser"
+	"testing"
+)
+
+type zeroRNG struct{}
+
+func (zeroRNG) Intn(n int) int {
+	return 0
+}
+
+func TestNew(t *testing.T) {
+	d := New(zeroRNG{})
+	if d.Remaining() != 52 {
+		t.Errorf("New(...).Remaining() == %d but expected 52", d.Remaining())
+	}
+	cards, _ := d.Deal(52)
+	if fmt.Sprint(cards) != fmt.Sprint(parser.Cards()) {
+		t.Errorf("New(...) deals %v but expected the cards in order", cards)
+	}
+}
+
+func TestShuffle(t *testing.T) {
+	// Always swapping with the first card rotates the deck by one.
+	d := New(zeroRNG{})
+	d.Shuffle()
+	cards, _ := d.Deal(3)
+	if fmt.Sprint(cards) != "[3H 4H 5H]" {
+		t.Errorf("Shuffle with an RNG of zeroes dealt %v but expected [3H 4H 5H]", cards)
+	}
+
+	first := New(NewSeededRNG(7))
+	second := New(NewSeededRNG(7))
+	first.Shuffle()
+	second.Shuffle()
+	firstCards, _ := first.Deal(52)
+	secondCards, _ := second.Deal(52)
+	if fmt.Sprint(firstCards) != fmt.Sprint(secondCards) {
+		t.Errorf("Shuffle with the same seed dealt %v and %v", firstCards, secondCards)
+	}
+
+	seen := make(map[string]bool)
+	for _, card := range firstCards {
+		if !parser.IsCardValid(card) || seen[card] {
+			t.Errorf("Shuffle dealt %q twice or not at all", card)
+		}
+		seen[card] = true
+	}
+
+	crypto := New(NewCryptoRNG())
+	crypto.Shuffle()
+	if crypto.Remaining() != 52 {
+		t.Errorf("Shuffle with crypto/rand left %d cards but expected 52", crypto.Remaining())
+	}
+}
+
+func TestNewWithoutRNG(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("New(nil) returned a deck but expected a panic")
+		}
+	}()
+	New(nil)
+}
+
+func TestDealAndBurn(t *testing.T) {
+	d := New(zeroRNG{})
+
+	if err := d.Burn(); err != nil {
+		t.Errorf("Burn() err == %q but expected nil", err)
+	}
+	flop, err := d.Deal(3)
+	if err != nil {
+		t.Errorf("Deal(3) err == %q but expected nil", err)
+	}
+	if fmt.Sprint(flop) != "[3H 4H 5H]" || fmt.Sprint(d.Burned()) != "[2H]" {
+		t.Errorf("Burn then Deal(3) dealt %v and burned %v but expected [3H 4H 5H] and [2H]", flop, d.Burned())
+	}
+	if d.Remaining() != 48 {
+		t.Errorf("Remaining() == %d but expected 48", d.Remaining())
+	}
+
+	if _, err := d.Deal(49); err == nil || err.Error() != "Invalid deal: 49 cards asked for but 48 remain" {
+		t.Errorf("Deal(49) err == %v but expected the deal to fail", err)
+	}
+	d.Deal(48)
+	if err := d.Burn(); err == nil {
+		t.Errorf("Burn() on an empty deck err == nil but expected an error")
+	}
+}
+
+func TestRemove(t *testing.T) {
+	d := New(NewSeededRNG(1))
+
+	if err := d.Remove("AH", "KD"); err != nil {
+		t.Errorf("Remove(AH, KD) err == %q but expected nil", err)
+	}
+	if d.Remaining() != 50 {
+		t.Errorf("Remaining() after Remove == %d but expected 50", d.Remaining())
+	}
+	d.Shuffle()
+	cards, _ := d.Deal(50)
+	for _, card := range cards {
+		if card == "AH" || card == "KD" {
+			t.Errorf("Deal after Remove(AH, KD) dealt %q", card)
+		}
+	}
+
+	if err := New(zeroRNG{}).Remove("AP"); err == nil || err.Error() != "Invalid card: AP is not in the deck" {
+		t.Errorf("Remove(AP) err == %v but expected the card not to be found", err)
+	}
+	d = New(zeroRNG{})
+	d.Remove("AH")
+	if err := d.Remove("QS", "AH"); err == nil || err.Error() != "Invalid card: AH is not in the deck" || d.Remaining() != 51 {
+		t.Errorf("Remove(QS, AH) err == %v with %d left but expected nothing removed", err, d.Remaining())
+	}
+	if err := d.Remove("QS", "QS"); err == nil || d.Remaining() != 51 {
+		t.Errorf("Remove(QS, QS) err == %v with %d left but expected nothing removed", err, d.Remaining())
+	}
+}
+
+func TestSample(t *testing.T) {
+	d := New(NewSeededRNG(1))
+	d.Remove("AH", "KD")
+
+	seen := map[string]bool{}
+	for i := 0; i < 200; i++ {
+		cards, err := d.Sample(5)
+		if err != nil {
+			t.Fatalf("Sample(5) err == %q but expected nil", err)
+		}
+		if len(cards) != 5 {
+			t.Fatalf("Sample(5) == %v but expected five cards", cards)
+		}
+		for j, card := range cards {
+			if card == "AH" || card == "KD" {
+				t.Errorf("Sample after Remove(AH, KD) picked %q", card)
+			}
+			for _, other := range cards[:j] {
+				if card == other {
+					t.Errorf("Sample(5) == %v but picked %q twice", cards, card)
+				}
+			}
+			seen[card] = true
+		}
+	}
+	if len(seen) != 50 || d.Remaining() != 50 {
+		t.Errorf("200 samples picked %d cards leaving %d but expected all 50 and nothing dealt", len(seen), d.Remaining())
+	}
+
+	if _, err := d.Sample(51); err == nil || err.Error() != "Invalid deal: 51 cards asked for but 50 remain" {
+		t.Errorf("Sample(51) err == %v but expected the sample to fail", err)
+	}
+	stacked, _ := NewStacked([]string{"AS", "KS"})
+	if cards, _ := stacked.Sample(2); fmt.Sprint(cards) != "[AS KS]" {
+		t.Errorf("stacked Sample(2) == %v but expected [AS KS]", cards)
+	}
+}
+
+func TestReshuffle(t *testing.T) {
+	d, _ := NewStacked([]string{"2D", "AS", "KS"})
+	d.Burn()
+	held, _ := d.Deal(50)
+	d.Reshuffle(held[0])
+	cards, err := d.Deal(3)
+	if err != nil || fmt.Sprint(cards) != "[AD 2D AS]" || len(d.Burned()) != 0 {
+		t.Errorf("Reshuffle(AS) dealt %v with %v burned but expected [AD 2D AS] with nothing burned", cards, d.Burned())
+	}
+
+	d = New(NewSeededRNG(1))
+	d.Shuffle()
+	d.Burn()
+	held, _ = d.Deal(49)
+	last := fmt.Sprint(d.cards)
+	d.Reshuffle(held[:10]...)
+	cards, _ = d.Deal(13)
+	if fmt.Sprint(cards[:2]) != last || d.Remaining() != 0 {
+		t.Errorf("Reshuffle dealt %v with %d left but expected %v first and every card used", cards, d.Remaining(), last)
+	}
+}
+
+func TestNewStacked(t *testing.T) {
+	d, err := NewStacked([]string{"AS", "KS", "2H"})
+	if err != nil {
+		t.Fatalf("NewStacked err == %q but expected nil", err)
+	}
+	d.Shuffle()
+	cards, _ := d.Deal(5)
+	if fmt.Sprint(cards) != "[AS KS 2H 3H 4H]" || d.Remaining() != 47 {
+		t.Errorf("NewStacked([AS KS 2H]) dealt %v with %d left but expected [AS KS 2H 3H 4H] with 47 left",
+			cards, d.Remaining())
+	}
+
+	if _, err := NewStacked([]string{"AS", "AS"}); err == nil {
+		t.Errorf("NewStacked([AS AS]) err == nil but expected an error")
+	}
+}
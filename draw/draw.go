@@ -0,0 +1,430 @@
+package draw // github.com/sildani/poker-hands-go/draw
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sildani/poker-hands-go/betting"
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/evaluator"
+	"github.com/sildani/poker-hands-go/pot"
+)
+
+const (
+	BeforeDraw = iota
+	Drawing
+	AfterDraw
+	Showdown
+)
+
+const Fold = betting.Fold
+const Check = betting.Check
+const Call = betting.Call
+const Bet = betting.Bet
+const Raise = betting.Raise
+const Draw = "draw"
+
+// Events that are not player decisions.
+const Ante = "ante"
+const SmallBlind = "small blind"
+const BigBlind = "big blind"
+const Return = betting.Return
+const Win = betting.Win
+
+// Openers is the least hand that may open the betting when Config.Openers
+// is set: a pair of jacks.
+const Openers = evaluator.Pair<<20 + 11<<16
+
+// Config sets the stakes. BetSize is the smallest bet, the big blind unless
+// set; in fixed-limit it doubles after the draw. With Openers set the game
+// is jacks or better: antes only, and the first bet before the draw needs a
+// pair of jacks or better. When nobody opens the antes go back to the
+// players, or with CarryOver set they stay in the pot for the next deal,
+// which is played with them as Carried. Limit, Cap and OddChip are as in
+// holdem.
+type Config struct {
+	Ante       int
+	SmallBlind int
+	BigBlind   int
+	BetSize    int
+	Limit      string
+	Cap        int
+	Openers    bool
+	CarryOver  bool
+	Carried    int
+	OddChip    string
+}
+
+type Seat struct {
+	Name  string
+	Stack int
+}
+
+type Player struct {
+	Name  string
+	Hand  []string
+	Drawn int
+	betting.Chips
+}
+
+// Action is a decision by the player to act. For a bet or raise Amount is
+// the total the player's bet comes to in this round; for a draw Discards
+// are the cards thrown away, none to stand pat.
+type Action struct {
+	Type     string
+	Amount   int
+	Discards []string
+}
+
+// Event is something that happened in the hand. For a call Amount is what
+// was put in, for a bet or raise the total bet in this round and for a
+// draw the number of cards drawn.
+type Event = betting.Event
+
+// Options are the legal actions for the player to act, worked out by the
+// betting package.
+type Options = betting.Options
+
+// Hand is a five-card draw hand as a state machine: NewHand posts and deals
+// five cards each, then each Act moves the hand through the betting, the
+// draw, the betting again and the showdown.
+type Hand struct {
+	config   Config
+	players  []*Player
+	button   int
+	deck     *deck.Deck
+	table    betting.Table
+	discards []string
+	passed   bool
+	pots     []pot.Pot
+}
+
+func NewHand(config Config, seats []Seat, button int, d *deck.Deck) (*Hand, error) {
+	if len(seats) < 2 || len(seats) > 6 {
+		return nil, errors.New("Invalid table: must have two to six players")
+	}
+	if config.BetSize == 0 {
+		config.BetSize = config.BigBlind
+	}
+	if config.BetSize <= 0 || config.SmallBlind < 0 || config.SmallBlind > config.BigBlind || config.Ante < 0 {
+		return nil, errors.New("Invalid stakes: bet size must be positive and the big blind at least the small blind")
+	}
+	if config.Openers && (config.SmallBlind > 0 || config.BigBlind > 0 || config.Ante == 0) {
+		return nil, errors.New("Invalid stakes: jacks or better is played with antes and no blinds")
+	}
+	if config.Carried < 0 || config.Carried > 0 && !(config.Openers && config.CarryOver) {
+		return nil, errors.New("Invalid stakes: only jacks or better with carry-over carries chips into a deal")
+	}
+	if button < 0 || button >= len(seats) {
+		return nil, fmt.Errorf("Invalid button: must be a seat from 0 to %d", len(seats)-1)
+	}
+	if config.Limit != "" && config.Limit != betting.NoLimit && config.Limit != betting.PotLimit &&
+		config.Limit != betting.FixedLimit {
+		return nil, fmt.Errorf("Invalid limit: unknown betting structure %q", config.Limit)
+	}
+
+	h := &Hand{config: config, button: button, deck: d, discards: []string{}}
+	chips := []*betting.Chips{}
+	for _, seat := range seats {
+		if seat.Stack <= 0 {
+			return nil, fmt.Errorf("Invalid seat: %s has no chips", seat.Name)
+		}
+		p := &Player{Name: seat.Name, Hand: []string{}, Chips: betting.Chips{Stack: seat.Stack}}
+		h.players = append(h.players, p)
+		chips = append(chips, &p.Chips)
+	}
+	h.table = betting.NewTable(chips)
+	h.table.Dead = config.Carried
+
+	if config.Ante > 0 {
+		for i := range h.players {
+			h.table.Post(i, Ante, config.Ante)
+		}
+		for _, p := range h.players {
+			p.Bet = 0
+		}
+	}
+
+	h.table.Round = betting.NewRound(config.Limit, config.BetSize, config.Cap)
+	h.table.ToAct = button
+	if config.BigBlind > 0 {
+		smallBlind, bigBlind := h.next(button), h.next(h.next(button))
+		if len(seats) == 2 {
+			smallBlind, bigBlind = button, h.next(button)
+		}
+		h.table.Post(smallBlind, SmallBlind, config.SmallBlind)
+		h.table.Post(bigBlind, BigBlind, config.BigBlind)
+		h.table.Round.CurrentBet = max(h.players[smallBlind].Bet, h.players[bigBlind].Bet)
+		h.table.Round.FullBet = config.BigBlind
+		h.table.Round.Raises = 1
+		h.table.ToAct = bigBlind
+	}
+
+	for round := 0; round < 5; round++ {
+		for i := range h.players {
+			card, err := d.Deal(1)
+			if err != nil {
+				return nil, err
+			}
+			seat := (button + 1 + i) % len(h.players)
+			h.players[seat].Hand = append(h.players[seat].Hand, card[0])
+		}
+	}
+
+	h.advance()
+	return h, nil
+}
+
+func (h *Hand) Players() []Player {
+	players := []Player{}
+	for _, p := range h.players {
+		player := *p
+		player.Hand = append([]string{}, p.Hand...)
+		players = append(players, player)
+	}
+	return players
+}
+
+func (h *Hand) Button() int {
+	return h.button
+}
+
+func (h *Hand) Street() int {
+	return h.table.Street
+}
+
+// ToAct is the seat to act, or -1 once the hand is over.
+func (h *Hand) ToAct() int {
+	if h.Done() {
+		return -1
+	}
+	return h.table.ToAct
+}
+
+func (h *Hand) Done() bool {
+	return h.table.Street == Showdown
+}
+
+// Passed is true when nobody opened in jacks or better. By default the
+// antes go back to the players; with Config.CarryOver they stay in the pot
+// and Carried is what goes into the next deal.
+func (h *Hand) Passed() bool {
+	return h.passed
+}
+
+// Carried is the pot a passed deal with carry-over leaves for the next
+// one: the antes and whatever was carried into this deal. Otherwise it is
+// zero.
+func (h *Hand) Carried() int {
+	if !h.passed {
+		return 0
+	}
+	return h.table.Pot()
+}
+
+func (h *Hand) CurrentBet() int {
+	return h.table.Round.CurrentBet
+}
+
+func (h *Hand) Pot() int {
+	return h.table.Pot()
+}
+
+func (h *Hand) Events() []Event {
+	return append([]Event{}, h.table.Events...)
+}
+
+// Pots are the main pot and any side pots once the hand is over. Win
+// events come in the same order.
+func (h *Hand) Pots() []pot.Pot {
+	return append([]pot.Pot{}, h.pots...)
+}
+
+// Winnings is what each seat took from the pot once the hand is over.
+func (h *Hand) Winnings() []int {
+	return append([]int{}, h.table.Winnings...)
+}
+
+// Options are the betting options for the player to act, none during the
+// draw. Without openers a player may check or fold but not bet.
+func (h *Hand) Options() Options {
+	if h.Done() || h.table.Street == Drawing {
+		return Options{}
+	}
+	options := h.table.Options()
+	if h.needsOpeners() && evaluator.Score(h.players[h.table.ToAct].Hand) < Openers {
+		options.MinRaise, options.MaxRaise = 0, 0
+	}
+	return options
+}
+
+func (h *Hand) Act(action Action) error {
+	if h.Done() {
+		return errors.New("Invalid action: the hand is over")
+	}
+	seat := h.table.ToAct
+	p := h.players[seat]
+
+	if h.table.Street == Drawing {
+		if action.Type != Draw {
+			return fmt.Errorf("Invalid action: %s must draw", p.Name)
+		}
+		return h.draw(seat, action.Discards)
+	}
+	if action.Type == Draw {
+		return errors.New("Invalid action: it is not time to draw")
+	}
+	options := h.Options()
+	if action.Type == Bet && options.MinRaise == 0 && h.needsOpeners() {
+		return fmt.Errorf("Invalid action: %s needs jacks or better to open", p.Name)
+	}
+
+	if err := h.table.Act(p.Name, action.Type, action.Amount, options); err != nil {
+		return err
+	}
+	h.advance()
+	return nil
+}
+
+// draw swaps the discards for new cards from the deck, which had a card
+// burned before the draw began. When the deck runs short the last cards are
+// dealt and then the burn card and the earlier discards are shuffled into a
+// new stub, so that six players can all draw five.
+func (h *Hand) draw(seat int, discards []string) error {
+	p := h.players[seat]
+	kept := append([]string{}, p.Hand...)
+	for _, discard := range discards {
+		found := false
+		for i, card := range kept {
+			if card == discard {
+				kept = append(kept[:i], kept[i+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("Invalid draw: %s does not hold %s", p.Name, discard)
+		}
+	}
+
+	drawn, err := h.deck.Deal(min(len(discards), h.deck.Remaining()))
+	if err != nil {
+		return err
+	}
+	if len(drawn) < len(discards) {
+		h.deck.Reshuffle(h.discards...)
+		h.discards = []string{}
+		more, err := h.deck.Deal(len(discards) - len(drawn))
+		if err != nil {
+			return err
+		}
+		drawn = append(drawn, more...)
+	}
+	h.discards = append(h.discards, discards...)
+	p.Hand = append(kept, drawn...)
+	p.Drawn = len(drawn)
+	h.table.Events = append(h.table.Events, Event{Seat: seat, Street: Drawing, Type: Draw, Amount: len(drawn)})
+
+	h.table.Acted[seat] = true
+	h.advance()
+	return nil
+}
+
+// needsOpeners is true while nobody has opened the betting in jacks or
+// better.
+func (h *Hand) needsOpeners() bool {
+	return h.config.Openers && h.table.Street == BeforeDraw && h.table.Round.CurrentBet == 0
+}
+
+// advance moves on to the next player to act, moving to the draw or the
+// next round or settling the hand when everyone is done.
+func (h *Hand) advance() {
+	for !h.Done() {
+		if h.table.Contenders() == 1 {
+			h.table.ReturnUncalled()
+			h.settle()
+			return
+		}
+		next := h.table.Next
+		if h.table.Street == Drawing {
+			next = h.nextToDraw
+		}
+		if next() {
+			return
+		}
+
+		if h.needsOpeners() {
+			h.pass()
+			return
+		}
+		h.table.ReturnUncalled()
+		if h.table.Street == AfterDraw {
+			h.settle()
+			return
+		}
+		h.nextStreet()
+	}
+}
+
+// nextToDraw moves the turn to the next player still in who has not drawn,
+// all in or not, and is false when everyone has.
+func (h *Hand) nextToDraw() bool {
+	for i := 1; i <= len(h.players); i++ {
+		seat := (h.table.ToAct + i) % len(h.players)
+		if !h.players[seat].Folded && !h.table.Acted[seat] {
+			h.table.ToAct = seat
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Hand) nextStreet() {
+	betSize := h.config.BetSize
+	if h.config.Limit == betting.FixedLimit && h.table.Street+1 == AfterDraw {
+		betSize *= 2
+	}
+	h.table.NextStreet(betting.NewRound(h.config.Limit, betSize, h.config.Cap))
+	if h.table.Street == Drawing {
+		h.deck.Burn()
+	}
+
+	// Drawing and betting start left of the button; advance looks from the
+	// seat after ToAct.
+	h.table.ToAct = h.button
+}
+
+// pass ends a jacks or better hand nobody opened, giving back the antes
+// unless they carry over.
+func (h *Hand) pass() {
+	h.table.Street = Showdown
+	h.passed = true
+	for seat, p := range h.players {
+		if p.Total > 0 && !h.config.CarryOver {
+			p.Stack += p.Total
+			h.table.Events = append(h.table.Events, Event{Seat: seat, Street: BeforeDraw, Type: Return, Amount: p.Total})
+			p.Total = 0
+		}
+		p.AllIn = false
+	}
+}
+
+// settle builds the main and side pots and awards each to the best hand
+// eligible for it.
+func (h *Hand) settle() {
+	hands := [][]string{}
+	scores := make([]int, len(h.players))
+	for i, p := range h.players {
+		hands = append(hands, p.Hand)
+		if !p.Folded && h.table.Contenders() > 1 {
+			scores[i] = evaluator.Score(p.Hand)
+		}
+	}
+
+	h.pots = h.table.Settle(Showdown, func(p pot.Pot) []int {
+		return pot.Award([]pot.Pot{p}, scores, hands, h.button, h.config.OddChip)
+	})
+}
+
+func (h *Hand) next(seat int) int {
+	return (seat + 1) % len(h.players)
+}
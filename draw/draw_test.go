@@ -0,0 +1,213 @@
+package draw // github.com/sildani/poker-hands-go/draw
+
+import (
+	"fmt"
+	"github.com/sildani/poker-hands-go/betting"
+	"github.com/sildani/poker-hands-go/deck"
+	"testing"
+)
+
+// stacked deals hands (by seat) one card at a time starting left of the
+// button, burns 2D, then deals draws in order.
+func stacked(t *testing.T, button int, hands [][]string, draws []string) *deck.Deck {
+	cards := []string{}
+	for round := 0; round < 5; round++ {
+		for i := range hands {
+			cards = append(cards, hands[(button+1+i)%len(hands)][round])
+		}
+	}
+	cards = append(append(cards, "2D"), draws...)
+	d, err := deck.NewStacked(cards)
+	if err != nil {
+		t.Fatalf("deck.NewStacked(%v) err == %q", cards, err)
+	}
+	return d
+}
+
+func seats(stacks ...int) []Seat {
+	seats := []Seat{}
+	for i, stack := range stacks {
+		seats = append(seats, Seat{Name: fmt.Sprintf("Player %d", i+1), Stack: stack})
+	}
+	return seats
+}
+
+func act(t *testing.T, h *Hand, actions ...Action) {
+	for _, action := range actions {
+		seat := h.ToAct()
+		if err := h.Act(action); err != nil {
+			t.Fatalf("seat %d Act(%v) err == %q but expected nil", seat, action, err)
+		}
+	}
+}
+
+func TestNewHandInvalid(t *testing.T) {
+	tests := []struct {
+		config      Config
+		seats       []Seat
+		expectedErr string
+	}{
+		{Config{SmallBlind: 1, BigBlind: 2}, seats(100, 100, 100, 100, 100, 100, 100), "Invalid table: must have two to six players"},
+		{Config{Ante: 1}, seats(100, 100), "Invalid stakes: bet size must be positive and the big blind at least the small blind"},
+		{Config{SmallBlind: 1, BigBlind: 2, Ante: 1, Openers: true}, seats(100, 100), "Invalid stakes: jacks or better is played with antes and no blinds"},
+		{Config{BetSize: 2, Openers: true}, seats(100, 100), "Invalid stakes: jacks or better is played with antes and no blinds"},
+		{Config{SmallBlind: 1, BigBlind: 2}, seats(100, 0), "Invalid seat: Player 2 has no chips"},
+	}
+
+	for _, test := range tests {
+		_, err := NewHand(test.config, test.seats, 0, ordered())
+		if err == nil || err.Error() != test.expectedErr {
+			t.Errorf("NewHand(%v, %v) err == %v but expected %q", test.config, test.seats, err, test.expectedErr)
+		}
+	}
+}
+
+func TestDrawAndShowdown(t *testing.T) {
+	hands := [][]string{
+		{"AS", "AC", "7H", "8S", "9C"},
+		{"KS", "KC", "KH", "3S", "4C"},
+		{"2S", "5C", "6H", "JD", "QD"},
+	}
+	config := Config{SmallBlind: 1, BigBlind: 2, Limit: betting.FixedLimit}
+	h, _ := NewHand(config, seats(100, 100, 100), 0, stacked(t, 0, hands, []string{"2C", "2H", "AH", "AD", "5S"}))
+
+	if fmt.Sprint(h.Players()[0].Hand) != "[AS AC 7H 8S 9C]" {
+		t.Fatalf("seat 0 dealt %v but expected [AS AC 7H 8S 9C]", h.Players()[0].Hand)
+	}
+
+	act(t, h, Action{Type: Call}, Action{Type: Call}, Action{Type: Check})
+	if h.Street() != Drawing || h.ToAct() != 1 {
+		t.Fatalf("street %d with seat %d to act but expected the draw with seat 1 first", h.Street(), h.ToAct())
+	}
+	if err := h.Act(Action{Type: Check}); err == nil {
+		t.Errorf("Act(check) during the draw err == nil but expected a draw")
+	}
+	if err := h.Act(Action{Type: Draw, Discards: []string{"AS"}}); err == nil {
+		t.Errorf("Act(draw AS) for seat 1 err == nil but expected the card not to be held")
+	}
+
+	// Seat 1 draws two to trips and makes a full house, seat 2 stands pat and
+	// seat 0 draws three to aces and makes four of a kind.
+	act(t, h, Action{Type: Draw, Discards: []string{"3S", "4C"}},
+		Action{Type: Draw},
+		Action{Type: Draw, Discards: []string{"7H", "8S", "9C"}})
+	if fmt.Sprint(h.Players()[1].Hand) != "[KS KC KH 2C 2H]" || h.Players()[0].Drawn != 3 {
+		t.Errorf("after the draw hands are %v but expected seat 1 to hold a full house", h.Players())
+	}
+	if h.Street() != AfterDraw || h.Options().MinRaise != 4 {
+		t.Errorf("after the draw street %d with Options() %+v but expected a bet of 4", h.Street(), h.Options())
+	}
+
+	act(t, h, Action{Type: Bet, Amount: 4}, Action{Type: Fold}, Action{Type: Raise, Amount: 8}, Action{Type: Call})
+	if !h.Done() || fmt.Sprint(h.Winnings()) != "[22 0 0]" {
+		t.Errorf("Done() == %t with winnings %v but expected seat 0 to win 22", h.Done(), h.Winnings())
+	}
+}
+
+func TestOpeners(t *testing.T) {
+	hands := [][]string{
+		{"AS", "KC", "7H", "8S", "9C"},
+		{"JS", "JC", "3H", "4S", "6C"},
+		{"TS", "TC", "6H", "7D", "8D"},
+	}
+	config := Config{Ante: 1, BetSize: 2, Limit: betting.FixedLimit, Openers: true}
+	h, _ := NewHand(config, seats(100, 100, 100), 0, stacked(t, 0, hands, []string{}))
+
+	if options := h.Options(); h.ToAct() != 1 || options.MinRaise != 2 {
+		t.Errorf("seat %d to act with Options() %+v but expected seat 1 able to open", h.ToAct(), options)
+	}
+	act(t, h, Action{Type: Check})
+	if err := h.Act(Action{Type: Bet, Amount: 2}); err == nil || err.Error() != "Invalid action: Player 3 needs jacks or better to open" {
+		t.Errorf("Act(bet) with tens err == %v but expected openers to be needed", err)
+	}
+
+	act(t, h, Action{Type: Check}, Action{Type: Check})
+	if !h.Done() || !h.Passed() || h.Pot() != 0 {
+		t.Errorf("Done() == %t and Passed() == %t with pot %d but expected the hand passed and the antes returned",
+			h.Done(), h.Passed(), h.Pot())
+	}
+	for i, p := range h.Players() {
+		if p.Stack != 100 {
+			t.Errorf("seat %d stack == %d after the hand passed but expected 100", i, p.Stack)
+		}
+	}
+}
+
+func TestCarryOver(t *testing.T) {
+	hands := [][]string{
+		{"AS", "KC", "7H", "8S", "9C"},
+		{"JS", "JC", "3H", "4S", "6C"},
+		{"TS", "TC", "6H", "7D", "8D"},
+	}
+	config := Config{Ante: 1, BetSize: 2, Limit: betting.FixedLimit, Openers: true, CarryOver: true}
+	h, _ := NewHand(config, seats(100, 100, 100), 0, stacked(t, 0, hands, []string{}))
+	act(t, h, Action{Type: Check}, Action{Type: Check}, Action{Type: Check})
+	if !h.Passed() || h.Carried() != 3 || fmt.Sprint(h.Winnings()) != "[0 0 0]" {
+		t.Errorf("Passed() == %t carrying %d with winnings %v but expected the 3 in antes carried over",
+			h.Passed(), h.Carried(), h.Winnings())
+	}
+	for i, p := range h.Players() {
+		if p.Stack != 99 {
+			t.Errorf("seat %d stack == %d after the hand passed but expected 99", i, p.Stack)
+		}
+	}
+
+	config.Carried = h.Carried()
+	h, _ = NewHand(config, seats(99, 99, 99), 0, stacked(t, 0, hands, []string{}))
+	if h.Pot() != 6 {
+		t.Errorf("Pot() == %d but expected the 3 carried in and 3 in antes", h.Pot())
+	}
+	act(t, h, Action{Type: Bet, Amount: 2}, Action{Type: Fold}, Action{Type: Fold})
+	if h.Passed() || h.Carried() != 0 || fmt.Sprint(h.Winnings()) != "[0 6 0]" {
+		t.Errorf("Passed() == %t carrying %d with winnings %v but expected seat 1 to win the 6",
+			h.Passed(), h.Carried(), h.Winnings())
+	}
+
+	config.CarryOver = false
+	if _, err := NewHand(config, seats(99, 99, 99), 0, ordered()); err == nil ||
+		err.Error() != "Invalid stakes: only jacks or better with carry-over carries chips into a deal" {
+		t.Errorf("NewHand carrying 3 without carry-over err == %v but expected an error", err)
+	}
+}
+
+func TestSixPlayersDrawFive(t *testing.T) {
+	config := Config{SmallBlind: 1, BigBlind: 2}
+	h, _ := NewHand(config, seats(100, 100, 100, 100, 100, 100), 0, deck.New(deck.NewSeededRNG(7)))
+	act(t, h, Action{Type: Call}, Action{Type: Call}, Action{Type: Call}, Action{Type: Call},
+		Action{Type: Call}, Action{Type: Check})
+
+	// 30 cards are dealt and one burned, so the last two to draw get the
+	// rest of the deck and then the burn card and earlier discards.
+	before := h.Players()
+	for h.Street() == Drawing {
+		seat := h.ToAct()
+		act(t, h, Action{Type: Draw, Discards: h.Players()[seat].Hand})
+	}
+
+	held := map[string]bool{}
+	for i, p := range h.Players() {
+		if len(p.Hand) != 5 || p.Drawn != 5 {
+			t.Errorf("seat %d holds %v after drawing %d but expected five new cards", i, p.Hand, p.Drawn)
+		}
+		for _, card := range p.Hand {
+			if held[card] {
+				t.Errorf("seat %d drew %s, which another seat holds", i, card)
+			}
+			for _, discard := range before[i].Hand {
+				if card == discard {
+					t.Errorf("seat %d drew back its own discard %s", i, card)
+				}
+			}
+			held[card] = true
+		}
+	}
+	if h.Street() != AfterDraw {
+		t.Errorf("after six players drew five the street == %d but expected the betting after the draw", h.Street())
+	}
+}
+
+// ordered is a deck that deals the cards in order, hearts first.
+func ordered() *deck.Deck {
+	d, _ := deck.NewStacked(nil)
+	return d
+}
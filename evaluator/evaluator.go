@@ -10,8 +10,40 @@ import (
 
 const straightFlushBaseScore = 900
 const fourOfAKindBaseScore = 800
+const fullHouseBaseScore = 700
+const flushBaseScore = 600
+const straightBaseScore = 500
+const threeOfAKindBaseScore = 400
+const twoPairsBaseScore = 300
+const pairBaseScore = 200
 const highCardBaseScore = 100
 
+// Hand categories, lowest to highest. An Evaluation's category is the
+// hundreds digit of its first score.
+const (
+	HighCard = iota + 1
+	Pair
+	TwoPairs
+	ThreeOfAKind
+	Straight
+	Flush
+	FullHouse
+	FourOfAKind
+	StraightFlush
+)
+
+var categoryNames = map[int]string{
+	HighCard:      "High Card",
+	Pair:          "Pair",
+	TwoPairs:      "Two Pairs",
+	ThreeOfAKind:  "Three of a kind",
+	Straight:      "Straight",
+	Flush:         "Flush",
+	FullHouse:     "Full House",
+	FourOfAKind:   "Four of a kind",
+	StraightFlush: "Straight Flush",
+}
+
 type Stats struct {
 	suits  map[string]int
 	values map[int]int
@@ -25,6 +57,22 @@ type Evaluation struct {
 	}
 }
 
+func CategoryName(category int) string {
+	return categoryNames[category]
+}
+
+func (e Evaluation) Hand() string {
+	return e.hand
+}
+
+func (e Evaluation) Category() int {
+	return e.result[0].score / 100
+}
+
+func (e Evaluation) Description() string {
+	return e.result[0].description
+}
+
 func EvaluateParsedHand(parsedHand []string) Evaluation {
 	stats, _ := gatherStats(parsedHand)
 
@@ -40,45 +88,40 @@ func EvaluateParsedHand(parsedHand []string) Evaluation {
 	}
 
 	if isStraightFlush(stats) {
-		sortedValues := []int{0, 0, 0, 0, 0}
-		i := 0
-		for k, _ := range stats.values {
-			sortedValues[i] = k
-			i++
-		}
-		sort.Sort(sort.Reverse(sort.IntSlice(sortedValues)))
-
-		for j, value := range sortedValues {
-			result[j] = struct {
-				score       int
-				description string
-			}{
-				score:       straightFlushBaseScore + value,
-				description: "Straight Flush, High Card: " + strconv.Itoa(value),
-			}
+		for i, value := range straightValues(stats) {
+			result[i] = entry(straightFlushBaseScore+value, "Straight Flush, High Card: "+strconv.Itoa(value))
 		}
 	} else if isFourOfAKind(stats) {
-		for value, count := range stats.values {
-			if count == 4 {
-				result[0] = struct {
-					score       int
-					description string
-				}{
-					score:       fourOfAKindBaseScore + value,
-					description: "Four of a kind, High Card: " + strconv.Itoa(value),
-				}
-			} else {
-				for i := 1; i < 5; i++ {
-					result[i] = struct {
-						score       int
-						description string
-					}{
-						score:       highCardBaseScore + value,
-						description: "High Card: " + strconv.Itoa(value),
-					}
-				}
-			}
+		value := valuesWithCount(stats, 4)[0]
+		result[0] = entry(fourOfAKindBaseScore+value, "Four of a kind, High Card: "+strconv.Itoa(value))
+		fillHighCards(&result, 1, valuesWithCount(stats, 1))
+	} else if isFullHouse(stats) {
+		value := valuesWithCount(stats, 3)[0]
+		result[0] = entry(fullHouseBaseScore+value, "Full House, High Card: "+strconv.Itoa(value))
+		fillHighCards(&result, 1, valuesWithCount(stats, 2))
+	} else if isFlush(stats) {
+		for i, value := range valuesWithCount(stats, 1) {
+			result[i] = entry(flushBaseScore+value, "Flush, High Card: "+strconv.Itoa(value))
+		}
+	} else if isStraight(stats) {
+		for i, value := range straightValues(stats) {
+			result[i] = entry(straightBaseScore+value, "Straight, High Card: "+strconv.Itoa(value))
 		}
+	} else if isThreeOfAKind(stats) {
+		value := valuesWithCount(stats, 3)[0]
+		result[0] = entry(threeOfAKindBaseScore+value, "Three of a kind, High Card: "+strconv.Itoa(value))
+		fillHighCards(&result, 1, valuesWithCount(stats, 1))
+	} else if isTwoPairs(stats) {
+		for i, value := range valuesWithCount(stats, 2) {
+			result[i] = entry(twoPairsBaseScore+value, "Two Pairs, High Card: "+strconv.Itoa(value))
+		}
+		fillHighCards(&result, 2, valuesWithCount(stats, 1))
+	} else if isPair(stats) {
+		value := valuesWithCount(stats, 2)[0]
+		result[0] = entry(pairBaseScore+value, "Pair, High Card: "+strconv.Itoa(value))
+		fillHighCards(&result, 1, valuesWithCount(stats, 1))
+	} else {
+		fillHighCards(&result, 0, valuesWithCount(stats, 1))
 	}
 
 	return Evaluation{
@@ -87,6 +130,73 @@ func EvaluateParsedHand(parsedHand []string) Evaluation {
 	}
 }
 
+// EvaluateBestHand evaluates every five card combination of cards, which
+// must hold at least five cards, and returns the best one. This is the path
+// for seven card games such as Hold'em.
+func EvaluateBestHand(cards []string) Evaluation {
+	best := Evaluation{}
+	hand := make([]string, 5)
+
+	var choose func(start, depth int)
+	choose = func(start, depth int) {
+		if depth == 5 {
+			evaluation := EvaluateParsedHand(hand)
+			if Compare(evaluation, best) > 0 {
+				best = evaluation
+			}
+			return
+		}
+		for i := start; i <= len(cards)-(5-depth); i++ {
+			hand[depth] = cards[i]
+			choose(i+1, depth+1)
+		}
+	}
+	choose(0, 0)
+
+	return best
+}
+
+// Compare returns 1 if a beats b, -1 if b beats a and 0 for a tie.
+func Compare(a, b Evaluation) int {
+	for i := range a.result {
+		if a.result[i].score > b.result[i].score {
+			return 1
+		}
+		if a.result[i].score < b.result[i].score {
+			return -1
+		}
+	}
+	return 0
+}
+
+func entry(score int, description string) struct {
+	score       int
+	description string
+} {
+	return struct {
+		score       int
+		description string
+	}{
+		score:       score,
+		description: description,
+	}
+}
+
+// fillHighCards scores the remaining cards from index on, repeating the
+// lowest card when there are fewer cards than places left.
+func fillHighCards(result *[5]struct {
+	score       int
+	description string
+}, index int, values []int) {
+	for i := index; i < len(result); i++ {
+		value := values[len(values)-1]
+		if i-index < len(values) {
+			value = values[i-index]
+		}
+		result[i] = entry(highCardBaseScore+value, "High Card: "+strconv.Itoa(value))
+	}
+}
+
 func gatherStats(parsedHand []string) (Stats, error) {
 	if len(parsedHand) != 5 {
 		return Stats{suits: map[string]int{"": 0}, values: map[int]int{0: 0}},
@@ -118,16 +228,61 @@ func gatherStats(parsedHand []string) (Stats, error) {
 }
 
 func isStraightFlush(stats Stats) bool {
-	values := make([]int, len(stats.values))
-	i := 0
-	for value, _ := range stats.values {
-		values[i] = value
-		i++
-	}
-	sort.Ints(values)
-	return values[len(values)-1]-values[0] == 4 && len(stats.suits) == 1
+	return isStraight(stats) && isFlush(stats)
 }
 
 func isFourOfAKind(stats Stats) bool {
-	return len(stats.suits) == 4 && len(stats.values) == 2
+	return len(valuesWithCount(stats, 4)) == 1
+}
+
+func isFullHouse(stats Stats) bool {
+	return len(valuesWithCount(stats, 3)) == 1 && len(valuesWithCount(stats, 2)) == 1
+}
+
+func isFlush(stats Stats) bool {
+	return len(stats.suits) == 1
+}
+
+func isStraight(stats Stats) bool {
+	return len(straightValues(stats)) == 5
+}
+
+func isThreeOfAKind(stats Stats) bool {
+	return len(valuesWithCount(stats, 3)) == 1 && len(stats.values) == 3
+}
+
+func isTwoPairs(stats Stats) bool {
+	return len(valuesWithCount(stats, 2)) == 2
+}
+
+func isPair(stats Stats) bool {
+	return len(valuesWithCount(stats, 2)) == 1 && len(stats.values) == 4
+}
+
+// valuesWithCount returns the values seen exactly count times, highest first.
+func valuesWithCount(stats Stats, count int) []int {
+	values := []int{}
+	for value, c := range stats.values {
+		if c == count {
+			values = append(values, value)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(values)))
+	return values
+}
+
+// straightValues returns the five values of a straight, highest first, with
+// the ace counting low in A 2 3 4 5. It returns nil when there is no straight.
+func straightValues(stats Stats) []int {
+	values := valuesWithCount(stats, 1)
+	if len(values) != 5 {
+		return nil
+	}
+	if values[0]-values[4] == 4 {
+		return values
+	}
+	if values[0] == 14 && values[1] == 5 {
+		return []int{5, 4, 3, 2, 1}
+	}
+	return nil
 }
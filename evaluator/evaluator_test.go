@@ -45,6 +45,168 @@ func TestEvaluateParsedHand(t *testing.T) {
 				},
 			},
 		},
+		{
+			[]string{
+				"AD", "2D", "3D", "4D", "5D",
+			},
+			Evaluation{
+				hand: "AD 2D 3D 4D 5D",
+				result: [5]struct {
+					score       int
+					description string
+				}{
+					{905, "Straight Flush, High Card: 5"},
+					{904, "Straight Flush, High Card: 4"},
+					{903, "Straight Flush, High Card: 3"},
+					{902, "Straight Flush, High Card: 2"},
+					{901, "Straight Flush, High Card: 1"},
+				},
+			},
+		},
+		{
+			[]string{
+				"2C", "2D", "2H", "3S", "3C",
+			},
+			Evaluation{
+				hand: "2C 2D 2H 3S 3C",
+				result: [5]struct {
+					score       int
+					description string
+				}{
+					{702, "Full House, High Card: 2"},
+					{103, "High Card: 3"},
+					{103, "High Card: 3"},
+					{103, "High Card: 3"},
+					{103, "High Card: 3"},
+				},
+			},
+		},
+		{
+			[]string{
+				"2S", "8S", "AS", "QS", "3S",
+			},
+			Evaluation{
+				hand: "2S 8S AS QS 3S",
+				result: [5]struct {
+					score       int
+					description string
+				}{
+					{614, "Flush, High Card: 14"},
+					{612, "Flush, High Card: 12"},
+					{608, "Flush, High Card: 8"},
+					{603, "Flush, High Card: 3"},
+					{602, "Flush, High Card: 2"},
+				},
+			},
+		},
+		{
+			[]string{
+				"9C", "TD", "JH", "QS", "KS",
+			},
+			Evaluation{
+				hand: "9C TD JH QS KS",
+				result: [5]struct {
+					score       int
+					description string
+				}{
+					{513, "Straight, High Card: 13"},
+					{512, "Straight, High Card: 12"},
+					{511, "Straight, High Card: 11"},
+					{510, "Straight, High Card: 10"},
+					{509, "Straight, High Card: 9"},
+				},
+			},
+		},
+		{
+			[]string{
+				"5C", "3D", "4H", "2S", "AS",
+			},
+			Evaluation{
+				hand: "5C 3D 4H 2S AS",
+				result: [5]struct {
+					score       int
+					description string
+				}{
+					{505, "Straight, High Card: 5"},
+					{504, "Straight, High Card: 4"},
+					{503, "Straight, High Card: 3"},
+					{502, "Straight, High Card: 2"},
+					{501, "Straight, High Card: 1"},
+				},
+			},
+		},
+		{
+			[]string{
+				"7C", "7D", "7H", "KS", "2S",
+			},
+			Evaluation{
+				hand: "7C 7D 7H KS 2S",
+				result: [5]struct {
+					score       int
+					description string
+				}{
+					{407, "Three of a kind, High Card: 7"},
+					{113, "High Card: 13"},
+					{102, "High Card: 2"},
+					{102, "High Card: 2"},
+					{102, "High Card: 2"},
+				},
+			},
+		},
+		{
+			[]string{
+				"7C", "7D", "KH", "KS", "2S",
+			},
+			Evaluation{
+				hand: "7C 7D KH KS 2S",
+				result: [5]struct {
+					score       int
+					description string
+				}{
+					{313, "Two Pairs, High Card: 13"},
+					{307, "Two Pairs, High Card: 7"},
+					{102, "High Card: 2"},
+					{102, "High Card: 2"},
+					{102, "High Card: 2"},
+				},
+			},
+		},
+		{
+			[]string{
+				"7C", "7D", "KH", "QS", "2S",
+			},
+			Evaluation{
+				hand: "7C 7D KH QS 2S",
+				result: [5]struct {
+					score       int
+					description string
+				}{
+					{207, "Pair, High Card: 7"},
+					{113, "High Card: 13"},
+					{112, "High Card: 12"},
+					{102, "High Card: 2"},
+					{102, "High Card: 2"},
+				},
+			},
+		},
+		{
+			[]string{
+				"2H", "3D", "5S", "9C", "KD",
+			},
+			Evaluation{
+				hand: "2H 3D 5S 9C KD",
+				result: [5]struct {
+					score       int
+					description string
+				}{
+					{113, "High Card: 13"},
+					{109, "High Card: 9"},
+					{105, "High Card: 5"},
+					{103, "High Card: 3"},
+					{102, "High Card: 2"},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -285,34 +447,55 @@ func TestIsFourOfAKind(t *testing.T) {
 	}
 }
 
-// TODO: Full House: 3 cards of the same value, with the remaining 2
-// cards forming a pair. Ranked by the value of the 3 cards.
-
-// TODO: Flush: Hand contains 5 cards of the same suit. Hands which
-// are both flushes are ranked using the rules for High Card.
-
-// TODO: Straight: Hand contains 5 cards with consecutive values.
-// Hands which both contain a straight are ranked by their
-// highest card.
-
-// TODO: Three of a Kind: Three of the cards in the hand have the
-// same value. Hands which both contain three of a kind are
-// ranked by the value of the 3 cards.
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a              []string
+		b              []string
+		expectedResult int
+	}{
+		// The examples from the README
+		{[]string{"2H", "3D", "5S", "9C", "KD"}, []string{"2C", "3H", "4S", "8C", "AH"}, -1},
+		{[]string{"2H", "4S", "4C", "2D", "4H"}, []string{"2S", "8S", "AS", "QS", "3S"}, 1},
+		{[]string{"2H", "3D", "5S", "9C", "KD"}, []string{"2C", "3H", "4S", "8C", "KH"}, 1},
+		{[]string{"2H", "3D", "5S", "9C", "KD"}, []string{"2D", "3H", "5C", "9S", "KH"}, 0},
+		// Kickers break ties between equal categories
+		{[]string{"7C", "7D", "KH", "QS", "2S"}, []string{"7H", "7S", "KD", "JS", "AS"}, -1},
+		{[]string{"7C", "7D", "KH", "QS", "2S"}, []string{"7H", "7S", "KD", "JS", "3S"}, 1},
+		{[]string{"7C", "7D", "KH", "KS", "2S"}, []string{"7H", "7S", "KD", "KC", "3S"}, -1},
+		// The wheel is the lowest straight
+		{[]string{"AC", "2D", "3H", "4S", "5S"}, []string{"2C", "3D", "4H", "5C", "6S"}, -1},
+	}
 
-// TODO: Two Pairs: The hand contains 2 different pairs. Hands
-// which both contain 2 pairs are ranked by the value of
-// their highest pair. Hands with the same highest pair
-// are ranked by the value of their other pair. If these
-// values are the same the hands are ranked by the value
-// of the remaining card.
+	for _, test := range tests {
+		result := Compare(EvaluateParsedHand(test.a), EvaluateParsedHand(test.b))
+		if result != test.expectedResult {
+			t.Errorf("Compare(%v, %v) == %d but expected %d", test.a, test.b, result, test.expectedResult)
+		}
+	}
+}
 
-// TODO: Pair: 2 of the 5 cards in the hand have the same value.
-// Hands which both contain a pair are ranked by the value of
-// the cards forming the pair. If these values are the same,
-// the hands are ranked by the values of the cards not
-// forming the pair, in decreasing order.
+func TestEvaluateBestHand(t *testing.T) {
+	tests := []struct {
+		cards            []string
+		expectedHand     string
+		expectedCategory int
+	}{
+		{[]string{"2H", "3D", "5S", "9C", "KD"}, "2H 3D 5S 9C KD", HighCard},
+		{[]string{"AH", "KH", "2D", "QH", "JH", "7C", "TH"}, "AH KH QH JH TH", StraightFlush},
+		{[]string{"AH", "AD", "KS", "KC", "KD", "7C", "7H"}, "AH AD KS KC KD", FullHouse},
+		{[]string{"2C", "3D", "4H", "5S", "6S", "AC"}, "2C 3D 4H 5S 6S", Straight},
+		{[]string{"QC", "QD", "4H", "4S", "9S", "9C", "2D"}, "QC QD 4H 9S 9C", TwoPairs},
+	}
 
-// TODO: High Card: Hands which do not fit any higher category are
-// ranked by the value of their highest card. If the highest
-// cards have the same value, the hands are ranked by the next
-// highest, and so on.
+	for _, test := range tests {
+		evaluation := EvaluateBestHand(test.cards)
+		if evaluation.Category() != test.expectedCategory {
+			t.Errorf("EvaluateBestHand(%v).Category() == %d but expected %d",
+				test.cards, evaluation.Category(), test.expectedCategory)
+		}
+		if evaluation.Hand() != test.expectedHand {
+			t.Errorf("EvaluateBestHand(%v).Hand() == %q but expected %q",
+				test.cards, evaluation.Hand(), test.expectedHand)
+		}
+	}
+}
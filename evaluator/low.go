@@ -0,0 +1,87 @@
+package evaluator // github.com/sildani/poker-hands-go/evaluator
+
+import (
+	"github.com/sildani/poker-hands-go/parser"
+	"sort"
+)
+
+const lowBase = 1 << 24
+
+// LowScore ranks the best ace-to-five low out of the cards as a single
+// number, higher for better lows like Score: aces are low, straights and
+// flushes do not count and pairs are bad. It takes five to seven cards,
+// or under five for a partial hand such as a razz player's up cards.
+func LowScore(cards []string) int {
+	if len(cards) <= 5 {
+		return lowBase - lowBadness(cards)
+	}
+
+	best := 0
+	hand := make([]string, 5)
+	var choose func(start, n int)
+	choose = func(start, n int) {
+		if n == 5 {
+			if score := lowBase - lowBadness(hand); score > best {
+				best = score
+			}
+			return
+		}
+		for i := start; i < len(cards); i++ {
+			hand[n] = cards[i]
+			choose(i+1, n+1)
+		}
+	}
+	choose(0, 0)
+	return best
+}
+
+// EightOrBetter is LowScore for a low of five different cards eight or
+// under, the qualifier for the low half of a hi-lo pot, and 0 for none.
+func EightOrBetter(cards []string) int {
+	score := LowScore(cards)
+	if lowBase-score >= 9<<16 {
+		return 0
+	}
+	return score
+}
+
+// lowBadness packs how many of a kind make up the cards, then the values
+// with aces as one, most of a kind and highest first. Lower is a better
+// low, so five different cards eight or under come below 9<<16.
+func lowBadness(cards []string) int {
+	var counts [14]int
+	for _, card := range cards {
+		value, _ := parser.ParseCardValue(card[:1])
+		if value == 14 {
+			value = 1
+		}
+		counts[value]++
+	}
+
+	values := []int{}
+	for value := 13; value >= 1; value-- {
+		if counts[value] > 0 {
+			values = append(values, value)
+		}
+	}
+	sort.SliceStable(values, func(i, j int) bool {
+		return counts[values[i]] > counts[values[j]]
+	})
+
+	// No pair, one pair, two pairs, trips, a full house and quads, in
+	// order of how bad they are for a low.
+	category := len(cards) - len(values)
+	if counts[values[0]] >= 3 {
+		category += 1 + counts[values[0]] - 3
+	}
+
+	badness := category << 20
+	shift := uint(16)
+	for _, value := range values {
+		for i := 0; i < counts[value]; i++ {
+			badness += value << shift
+			shift -= 4
+		}
+	}
+	return badness
+}
@@ -0,0 +1,50 @@
+package evaluator // github.com/sildani/poker-hands-go/evaluator
+
+import (
+	"testing"
+)
+
+func TestLowScore(t *testing.T) {
+	tests := []struct {
+		better []string
+		worse  []string
+	}{
+		// The wheel is the best low; straights and flushes do not count.
+		{[]string{"AH", "2H", "3H", "4H", "5H"}, []string{"AS", "2D", "3C", "4H", "6S"}},
+		{[]string{"8S", "5D", "4C", "3H", "2S"}, []string{"8H", "6D", "3C", "2H", "AS"}},
+		{[]string{"KS", "QD", "JC", "TH", "8S"}, []string{"2S", "2D", "3C", "4H", "5S"}},
+		{[]string{"2S", "2D", "3C", "4H", "5S"}, []string{"2C", "2H", "3S", "3H", "4S"}},
+		{[]string{"2C", "2H", "3S", "3H", "4S"}, []string{"AS", "AD", "AC", "2H", "3S"}},
+		// The best five of seven.
+		{[]string{"AS", "KD", "QC", "2H", "3S", "4D", "7C"}, []string{"AH", "2D", "3H", "4C", "8S", "KH", "KS"}},
+		// Partial hands.
+		{[]string{"AS", "7D"}, []string{"2S", "8D"}},
+		{[]string{"KS", "QD", "JC"}, []string{"2S", "2D", "3C"}},
+	}
+
+	for _, test := range tests {
+		if LowScore(test.better) <= LowScore(test.worse) {
+			t.Errorf("LowScore(%v) <= LowScore(%v) but expected it to be higher", test.better, test.worse)
+		}
+	}
+}
+
+func TestEightOrBetter(t *testing.T) {
+	tests := []struct {
+		cards     []string
+		expectLow bool
+	}{
+		{[]string{"AS", "2D", "3C", "4H", "5S"}, true},
+		{[]string{"8S", "7D", "6C", "5H", "4S"}, true},
+		{[]string{"9S", "5D", "4C", "3H", "2S"}, false},
+		{[]string{"AS", "AD", "3C", "4H", "5S"}, false},
+		{[]string{"KS", "QD", "AC", "2H", "3S", "7D", "8C"}, true},
+		{[]string{"KS", "QD", "AC", "2H", "3S", "3D", "9C"}, false},
+	}
+
+	for _, test := range tests {
+		if low := EightOrBetter(test.cards); (low > 0) != test.expectLow {
+			t.Errorf("EightOrBetter(%v) == %d but expected a low to be %t", test.cards, low, test.expectLow)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package evaluator // github.com/sildani/poker-hands-go/evaluator
+
+import (
+	"github.com/sildani/poker-hands-go/parser"
+	"math/bits"
+)
+
+const categoryScore = 1 << 20
+
+var suitIndex = map[byte]int{'C': 0, 'D': 1, 'H': 2, 'S': 3}
+
+// Score ranks the best five cards out of five to seven as a single number,
+// so higher scores win and equal scores tie. It gathers the same stats as
+// EvaluateParsedHand but skips the descriptions, which makes it the path to
+// use when enumerating or simulating many hands.
+//
+// Under five cards it ranks a partial hand, such as a stud player's up
+// cards: only quads, trips, two pairs and pairs count, then the high cards.
+func Score(cards []string) int {
+	var counts [15]int
+	var suitMasks [4]int
+	valueMask := 0
+
+	for _, card := range cards {
+		value, _ := parser.ParseCardValue(card[:1])
+		counts[value]++
+		valueMask |= 1 << uint(value)
+		suitMasks[suitIndex[card[1]]] |= 1 << uint(value)
+	}
+
+	for _, mask := range suitMasks {
+		if bits.OnesCount(uint(mask)) >= 5 {
+			if high := straightHigh(mask); high > 0 {
+				return StraightFlush*categoryScore + high<<16
+			}
+			return Flush*categoryScore + kickers(mask, 5)
+		}
+	}
+
+	quad, trips, pair, secondPair := 0, 0, 0, 0
+	for value := 14; value >= 2; value-- {
+		if counts[value] == 4 && quad == 0 {
+			quad = value
+		} else if counts[value] >= 3 && trips == 0 {
+			trips = value
+		} else if counts[value] >= 2 && pair == 0 {
+			pair = value
+		} else if counts[value] >= 2 && secondPair == 0 {
+			secondPair = value
+		}
+	}
+
+	if quad > 0 {
+		return FourOfAKind*categoryScore + quad<<16 + kickers(valueMask&^(1<<uint(quad)), 1)>>4
+	} else if trips > 0 && pair > 0 {
+		return FullHouse*categoryScore + trips<<16 + pair<<12
+	} else if high := straightHigh(valueMask); high > 0 {
+		return Straight*categoryScore + high<<16
+	} else if trips > 0 {
+		return ThreeOfAKind*categoryScore + trips<<16 + kickers(valueMask&^(1<<uint(trips)), 2)>>4
+	} else if secondPair > 0 {
+		return TwoPairs*categoryScore + pair<<16 + secondPair<<12 +
+			kickers(valueMask&^(1<<uint(pair))&^(1<<uint(secondPair)), 1)>>8
+	} else if pair > 0 {
+		return Pair*categoryScore + pair<<16 + kickers(valueMask&^(1<<uint(pair)), 3)>>4
+	}
+	return HighCard*categoryScore + kickers(valueMask, 5)
+}
+
+// ScoreCategory returns the hand category of a Score.
+func ScoreCategory(score int) int {
+	return score / categoryScore
+}
+
+// kickers packs the n highest values in mask four bits apiece, the highest
+// in bits 16 to 19.
+func kickers(mask int, n int) int {
+	packed := 0
+	shift := uint(16)
+	for value := 14; value >= 2 && n > 0; value-- {
+		if mask&(1<<uint(value)) != 0 {
+			packed += value << shift
+			shift -= 4
+			n--
+		}
+	}
+	return packed
+}
+
+// straightHigh returns the high card of the best straight in mask, or 0.
+func straightHigh(mask int) int {
+	if mask&(1<<14) != 0 {
+		mask |= 1 << 1
+	}
+	for high := 14; high >= 5; high-- {
+		if (mask>>uint(high-4))&0x1f == 0x1f {
+			return high
+		}
+	}
+	return 0
+}
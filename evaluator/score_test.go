@@ -0,0 +1,81 @@
+package evaluator // github.com/sildani/poker-hands-go/evaluator
+
+import (
+	"github.com/sildani/poker-hands-go/parser"
+	"math/rand"
+	"testing"
+)
+
+func TestScoreCategory(t *testing.T) {
+	tests := []struct {
+		cards            []string
+		expectedCategory int
+	}{
+		{[]string{"2H", "3D", "5S", "9C", "KD"}, HighCard},
+		{[]string{"2H", "2D", "5S", "9C", "KD", "7S", "8S"}, Pair},
+		{[]string{"2H", "2D", "5S", "5C", "KD", "KS", "8S"}, TwoPairs},
+		{[]string{"2H", "2D", "2S", "5C", "KD", "7S", "8S"}, ThreeOfAKind},
+		{[]string{"AH", "2D", "3S", "4C", "5D", "KS", "KH"}, Straight},
+		{[]string{"AH", "2H", "9H", "4H", "5D", "KH", "KS"}, Flush},
+		{[]string{"2H", "2D", "2S", "5C", "5D", "5S", "8S"}, FullHouse},
+		{[]string{"2H", "2D", "2S", "2C", "5D", "5S", "5H"}, FourOfAKind},
+		{[]string{"AH", "2H", "3H", "4H", "5H", "6D", "7D"}, StraightFlush},
+	}
+
+	for _, test := range tests {
+		category := ScoreCategory(Score(test.cards))
+		if category != test.expectedCategory {
+			t.Errorf("ScoreCategory(Score(%v)) == %d but expected %d", test.cards, category, test.expectedCategory)
+		}
+	}
+}
+
+func TestScorePartialHands(t *testing.T) {
+	tests := []struct {
+		better []string
+		worse  []string
+	}{
+		{[]string{"KS"}, []string{"QS"}},
+		{[]string{"2S", "2D"}, []string{"AS", "KS"}},
+		{[]string{"9S", "9D", "3C"}, []string{"9C", "9H", "2C"}},
+		{[]string{"2S", "2D", "2C", "3H"}, []string{"AS", "AD", "KC", "KH"}},
+		{[]string{"3S", "3D", "3C", "3H"}, []string{"AS", "AD", "AC", "KH"}},
+		// Four to a straight or a flush is no better than its high cards.
+		{[]string{"AS", "2D", "3C", "5H"}, []string{"2H", "3H", "4H", "5H"}},
+	}
+
+	for _, test := range tests {
+		if Score(test.better) <= Score(test.worse) {
+			t.Errorf("Score(%v) <= Score(%v) but expected it to be higher", test.better, test.worse)
+		}
+	}
+}
+
+func TestScoreAgreesWithEvaluateBestHand(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	cards := parser.Cards()
+
+	for i := 0; i < 2000; i++ {
+		rng.Shuffle(len(cards), func(i, j int) { cards[i], cards[j] = cards[j], cards[i] })
+		size := 5 + rng.Intn(3)
+		a := append([]string{}, cards[:size]...)
+		b := append([]string{}, cards[size:2*size]...)
+
+		evaluationA := EvaluateBestHand(a)
+		if ScoreCategory(Score(a)) != evaluationA.Category() {
+			t.Errorf("ScoreCategory(Score(%v)) == %d but EvaluateBestHand found %d",
+				a, ScoreCategory(Score(a)), evaluationA.Category())
+		}
+
+		expected := Compare(evaluationA, EvaluateBestHand(b))
+		result := 0
+		if Score(a) > Score(b) {
+			result = 1
+		} else if Score(a) < Score(b) {
+			result = -1
+		}
+		if result != expected {
+			t.Errorf("Score(%v) against Score(%v) gives %d but Compare gives %d", a, b, result, expected)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package fair // github.com/sildani/poker-hands-go/fair
+
+import (
+	"crypto/hmac"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/sildani/poker-hands-go/deck"
+)
+
+// A provably fair hand goes like this: the server picks a secret seed and
+// publishes its Commit before the hand, a player supplies the client seed,
+// the deck is shuffled with an RNG derived from both, and after the hand
+// the server reveals its seed in a HandRecord anyone can Verify.
+type HandRecord struct {
+	Commitment string   `json:"commitment"`
+	ServerSeed string   `json:"server_seed"`
+	ClientSeed string   `json:"client_seed"`
+	Nonce      int      `json:"nonce"`
+	Deck       []string `json:"deck"`
+}
+
+type seededRNG struct {
+	serverSeed string
+	clientSeed string
+	nonce      int
+	counter    int
+	buffer     []byte
+}
+
+func NewServerSeed() (string, error) {
+	seed := make([]byte, 32)
+	if _, err := cryptorand.Read(seed); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(seed), nil
+}
+
+// Commit is the SHA-256 of the server seed, published before the hand.
+func Commit(serverSeed string) string {
+	sum := sha256.Sum256([]byte(serverSeed))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewRNG derives an RNG from the seeds. Its numbers are HMAC-SHA256 blocks
+// keyed by the server seed over "clientSeed:nonce:counter".
+func NewRNG(serverSeed string, clientSeed string, nonce int) deck.RNG {
+	return &seededRNG{serverSeed: serverSeed, clientSeed: clientSeed, nonce: nonce}
+}
+
+// NewDeck returns a deck shuffled from the seeds.
+func NewDeck(serverSeed string, clientSeed string, nonce int) *deck.Deck {
+	d := deck.New(NewRNG(serverSeed, clientSeed, nonce))
+	d.Shuffle()
+	return d
+}
+
+// Record returns what to publish after the hand.
+func Record(serverSeed string, clientSeed string, nonce int) HandRecord {
+	cards, _ := NewDeck(serverSeed, clientSeed, nonce).Deal(52)
+	return HandRecord{
+		Commitment: Commit(serverSeed),
+		ServerSeed: serverSeed,
+		ClientSeed: clientSeed,
+		Nonce:      nonce,
+		Deck:       cards,
+	}
+}
+
+// Verify checks the revealed server seed against the commitment and
+// recomputes the deck order, returning it.
+func Verify(record HandRecord) ([]string, error) {
+	if Commit(record.ServerSeed) != record.Commitment {
+		return nil, errors.New("Invalid record: server seed does not match commitment")
+	}
+
+	cards, _ := NewDeck(record.ServerSeed, record.ClientSeed, record.Nonce).Deal(52)
+	if len(record.Deck) > len(cards) {
+		return cards, errors.New("Invalid record: deck has more than 52 cards")
+	}
+	for i, card := range record.Deck {
+		if card != cards[i] {
+			return cards, fmt.Errorf("Invalid record: card %d is %s but the seeds deal %s", i+1, card, cards[i])
+		}
+	}
+	return cards, nil
+}
+
+// Intn draws 32 bit numbers, rejecting those above the largest multiple of
+// n so that every result is equally likely.
+func (r *seededRNG) Intn(n int) int {
+	limit := (1 << 32) / uint64(n) * uint64(n)
+	for {
+		value := uint64(r.next())
+		if value < limit {
+			return int(value % uint64(n))
+		}
+	}
+}
+
+func (r *seededRNG) next() uint32 {
+	if len(r.buffer) < 4 {
+		mac := hmac.New(sha256.New, []byte(r.serverSeed))
+		fmt.Fprintf(mac, "%s:%d:%d", r.clientSeed, r.nonce, r.counter)
+		r.buffer = mac.Sum(nil)
+		r.counter++
+	}
+	value := binary.BigEndian.Uint32(r.buffer)
+	r.buffer = r.buffer[4:]
+	return value
+}
@@ -0,0 +1,100 @@
+package fair // github.com/sildani/poker-hands-go/fair
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCommit(t *testing.T) {
+	commitment := Commit("abc")
+	expected := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	if commitment != expected {
+		t.Errorf("Commit(%q) == %q but expected %q", "abc", commitment, expected)
+	}
+}
+
+func TestNewServerSeed(t *testing.T) {
+	first, err := NewServerSeed()
+	if err != nil {
+		t.Errorf("NewServerSeed() err == %q but expected nil", err)
+	}
+	second, _ := NewServerSeed()
+	if len(first) != 64 || first == second {
+		t.Errorf("NewServerSeed() == %q then %q but expected two different 64 digit seeds", first, second)
+	}
+}
+
+func TestNewDeck(t *testing.T) {
+	first, _ := NewDeck("server", "client", 1).Deal(52)
+	again, _ := NewDeck("server", "client", 1).Deal(52)
+	if fmt.Sprint(first) != fmt.Sprint(again) {
+		t.Errorf("NewDeck with the same seeds dealt %v and %v", first, again)
+	}
+
+	tests := []struct {
+		serverSeed string
+		clientSeed string
+		nonce      int
+	}{
+		{"other server", "client", 1},
+		{"server", "other client", 1},
+		{"server", "client", 2},
+	}
+	for _, test := range tests {
+		other, _ := NewDeck(test.serverSeed, test.clientSeed, test.nonce).Deal(52)
+		if fmt.Sprint(first) == fmt.Sprint(other) {
+			t.Errorf("NewDeck(%q, %q, %d) dealt the same deck as NewDeck(%q, %q, %d)",
+				test.serverSeed, test.clientSeed, test.nonce, "server", "client", 1)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	record := Record("server", "client", 1)
+
+	cards, err := Verify(record)
+	if err != nil {
+		t.Errorf("Verify(Record(...)) err == %q but expected nil", err)
+	}
+	if strings.Join(cards, " ") != strings.Join(record.Deck, " ") {
+		t.Errorf("Verify(Record(...)) == %v but expected %v", cards, record.Deck)
+	}
+
+	partial := record
+	partial.Deck = record.Deck[:9]
+	if _, err := Verify(partial); err != nil {
+		t.Errorf("Verify of the first nine cards err == %q but expected nil", err)
+	}
+
+	seed := record
+	seed.ServerSeed = "another server"
+	if _, err := Verify(seed); err == nil || err.Error() != "Invalid record: server seed does not match commitment" {
+		t.Errorf("Verify with a different server seed err == %v but expected a commitment mismatch", err)
+	}
+
+	stacked := record
+	stacked.Deck = append([]string{}, record.Deck...)
+	stacked.Deck[3], stacked.Deck[4] = stacked.Deck[4], stacked.Deck[3]
+	expectedErr := fmt.Sprintf("Invalid record: card 4 is %s but the seeds deal %s", record.Deck[4], record.Deck[3])
+	if _, err := Verify(stacked); err == nil || err.Error() != expectedErr {
+		t.Errorf("Verify of a stacked deck err == %v but expected %q", err, expectedErr)
+	}
+}
+
+func TestIntn(t *testing.T) {
+	rng := NewRNG("server", "client", 1)
+	counts := make([]int, 3)
+	for i := 0; i < 3000; i++ {
+		value := rng.Intn(3)
+		if value < 0 || value >= 3 {
+			t.Fatalf("Intn(3) == %d", value)
+		}
+		counts[value]++
+	}
+	for value, count := range counts {
+		if count < 900 || count > 1100 {
+			t.Errorf("Intn(3) returned %d %d times out of 3000", value, count)
+		}
+	}
+}
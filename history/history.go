@@ -0,0 +1,221 @@
+package history // github.com/sildani/poker-hands-go/history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/sildani/poker-hands-go/betting"
+	"github.com/sildani/poker-hands-go/holdem"
+	"io"
+)
+
+// Events a hand history records besides the holdem package's actions and
+// posts.
+const BothBlinds = "small & big blinds"
+const Show = "show"
+const Muck = "muck"
+
+// Engine is the site recorded for hands played on the holdem engine.
+const Engine = "poker-hands-go"
+
+// Hand is one hand as a hand history tells it. Amounts are whole chips, or
+// cents when Currency is set. Players go by name, as the histories have
+// them, and streets are numbered as in the holdem package. Hero is the
+// player whose history it is, who was dealt the cards, if any.
+type Hand struct {
+	Site       string   `json:"site,omitempty"`
+	ID         string   `json:"id"`
+	Tournament string   `json:"tournament,omitempty"`
+	BuyIn      string   `json:"buy_in,omitempty"`
+	Level      string   `json:"level,omitempty"`
+	Game       string   `json:"game"`
+	Currency   string   `json:"currency,omitempty"`
+	SmallBlind int      `json:"small_blind"`
+	BigBlind   int      `json:"big_blind"`
+	Ante       int      `json:"ante,omitempty"`
+	Date       string   `json:"date"`
+	Table      string   `json:"table"`
+	MaxSeats   int      `json:"max_seats"`
+	Button     int      `json:"button"`
+	Seats      []Seat   `json:"seats"`
+	Hero       string   `json:"hero,omitempty"`
+	Board      []string `json:"board,omitempty"`
+	Actions    []Action `json:"actions"`
+	Wins       []Win    `json:"wins"`
+	TotalPot   int      `json:"total_pot"`
+	Rake       int      `json:"rake"`
+}
+
+// Seat is a player at the table, by seat number. Hole is the cards the
+// history shows them holding, if any.
+type Seat struct {
+	Number     int      `json:"number"`
+	Name       string   `json:"name"`
+	Stack      int      `json:"stack"`
+	SittingOut bool     `json:"sitting_out,omitempty"`
+	Hole       []string `json:"hole,omitempty"`
+}
+
+// Action is something a player did. As with holdem events, for a call or a
+// post Amount is what was put in, for a bet or raise the total bet on the
+// street and for a return what came back; Cards are the cards shown.
+type Action struct {
+	Street int      `json:"street"`
+	Player string   `json:"player"`
+	Type   string   `json:"type"`
+	Amount int      `json:"amount,omitempty"`
+	AllIn  bool     `json:"all_in,omitempty"`
+	Cards  []string `json:"cards,omitempty"`
+}
+
+// Win is what a player collected from a pot: "pot", "main pot" or "side
+// pot-1" and so on.
+type Win struct {
+	Player string `json:"player"`
+	Amount int    `json:"amount"`
+	Pot    string `json:"pot"`
+}
+
+// Seat returns the seat of the named player, nil if there is none.
+func (h *Hand) Seat(name string) *Seat {
+	for i := range h.Seats {
+		if h.Seats[i].Name == name {
+			return &h.Seats[i]
+		}
+	}
+	return nil
+}
+
+// games names the holdem variants and betting structures as PokerStars
+// does.
+var games = map[string]string{"": "Hold'em", holdem.Holdem: "Hold'em", holdem.Omaha: "Omaha", holdem.OmahaHiLo: "Omaha Hi/Lo"}
+var limits = map[string]string{"": "No Limit", betting.NoLimit: "No Limit", betting.PotLimit: "Pot Limit", betting.FixedLimit: "Limit"}
+
+// FromHoldem records a finished hand from the holdem engine, played with
+// config. The site is Engine, seats are numbered from 1, everyone's hole
+// cards are kept and everyone left at the end shows down. The ID, Table
+// and Date are for the caller to fill in.
+func FromHoldem(h *holdem.Hand, config holdem.Config) Hand {
+	players := h.Players()
+	winnings := h.Winnings()
+	hand := Hand{
+		Site:       Engine,
+		Game:       games[config.Variant] + " " + limits[config.Limit],
+		SmallBlind: config.SmallBlind,
+		BigBlind:   config.BigBlind,
+		Ante:       config.Ante,
+		MaxSeats:   len(players),
+		Button:     h.Button() + 1,
+		Board:      h.Board(),
+		Actions:    []Action{},
+		Wins:       []Win{},
+	}
+
+	stacks := []int{}
+	contenders := 0
+	for i, p := range players {
+		stack := p.Stack - winnings[i] + p.Total
+		stacks = append(stacks, stack)
+		hand.Seats = append(hand.Seats, Seat{Number: i + 1, Name: p.Name, Stack: stack, Hole: p.Hole})
+		hand.TotalPot += p.Total
+		if !p.Folded {
+			contenders++
+		}
+	}
+
+	pots := h.Pots()
+	collected := 0
+	for _, event := range h.Events() {
+		name := players[event.Seat].Name
+		switch event.Type {
+		case holdem.Win:
+			for len(pots) > 1 && collected >= pots[0].Amount {
+				collected -= pots[0].Amount
+				pots = pots[1:]
+			}
+			collected += event.Amount
+			hand.Wins = append(hand.Wins, Win{Player: name, Amount: event.Amount, Pot: potName(len(h.Pots()), len(h.Pots())-len(pots))})
+			continue
+		case holdem.Return:
+			stacks[event.Seat] += event.Amount
+		case holdem.Bet, holdem.Raise:
+			stacks[event.Seat] -= event.Amount - hand.streetBet(hand.Actions, name, event.Street)
+		default:
+			stacks[event.Seat] -= event.Amount
+		}
+		hand.Actions = append(hand.Actions, Action{Street: event.Street, Player: name, Type: event.Type, Amount: event.Amount,
+			AllIn: event.Type != holdem.Return && event.Amount > 0 && stacks[event.Seat] == 0})
+	}
+
+	if contenders > 1 {
+		for i := range players {
+			seat := (h.Button() + 1 + i) % len(players)
+			if !players[seat].Folded {
+				hand.Actions = append(hand.Actions, Action{Street: holdem.Showdown, Player: players[seat].Name, Type: Show, Cards: players[seat].Hole})
+			}
+		}
+	}
+	return hand
+}
+
+// potName names pot i of n as PokerStars does.
+func potName(n int, i int) string {
+	if n == 1 {
+		return "pot"
+	}
+	if i == 0 {
+		return "main pot"
+	}
+	return fmt.Sprintf("side pot-%d", i)
+}
+
+// streetBet is what player has bet on street by the end of actions. The
+// small blind posted with a big blind is dead and does not count.
+func (h *Hand) streetBet(actions []Action, player string, street int) int {
+	bet := 0
+	for _, action := range actions {
+		if action.Player != player || action.Street != street {
+			continue
+		}
+		switch action.Type {
+		case holdem.SmallBlind, holdem.BigBlind, holdem.Call:
+			bet += action.Amount
+		case BothBlinds:
+			bet += action.Amount - h.SmallBlind
+		case holdem.Bet, holdem.Raise:
+			bet = action.Amount
+		case holdem.Return:
+			bet -= action.Amount
+		}
+	}
+	return bet
+}
+
+// WriteJSON writes the hands as JSON, one a line.
+func WriteJSON(w io.Writer, hands []Hand) error {
+	encoder := json.NewEncoder(w)
+	for _, hand := range hands {
+		if err := encoder.Encode(hand); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseJSON reads back hands written by WriteJSON.
+func ParseJSON(r io.Reader) ([]Hand, error) {
+	hands := []Hand{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var hand Hand
+		if err := json.Unmarshal(scanner.Bytes(), &hand); err != nil {
+			return nil, fmt.Errorf("Invalid hand history: line %d %v", line, err)
+		}
+		hands = append(hands, hand)
+	}
+	return hands, scanner.Err()
+}
@@ -0,0 +1,86 @@
+package history // github.com/sildani/poker-hands-go/history
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/holdem"
+	"strings"
+	"testing"
+)
+
+// allIn plays three players all in preflop, the short stack with aces on
+// the button, so there is a main pot and a side pot.
+func allIn(t *testing.T) (*holdem.Hand, holdem.Config) {
+	config := holdem.Config{SmallBlind: 1, BigBlind: 2}
+	d, _ := deck.NewStacked([]string{"KH", "QH", "AH", "KS", "QS", "AS", "2C", "3D", "7C", "9D", "4H", "JC", "5S", "8S"})
+	seats := []holdem.Seat{{Name: "A", Stack: 50}, {Name: "B", Stack: 100}, {Name: "C", Stack: 100}}
+	h, err := holdem.NewHand(config, seats, 0, d)
+	if err != nil {
+		t.Fatalf("holdem.NewHand err == %q", err)
+	}
+	for _, action := range []holdem.Action{{Type: holdem.Raise, Amount: 50}, {Type: holdem.Raise, Amount: 100}, {Type: holdem.Call}} {
+		if err := h.Act(action); err != nil {
+			t.Fatalf("Act(%+v) err == %q", action, err)
+		}
+	}
+	return h, config
+}
+
+func TestFromHoldem(t *testing.T) {
+	h, config := allIn(t)
+	hand := FromHoldem(h, config)
+
+	for _, test := range []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{"game", []interface{}{hand.Game, hand.SmallBlind, hand.BigBlind, hand.MaxSeats, hand.Button}, "[Hold'em No Limit 1 2 3 1]"},
+		{"seats", hand.Seats, "[{1 A 50 false [AH AS]} {2 B 100 false [KH KS]} {3 C 100 false [QH QS]}]"},
+		{"board", hand.Board, "[3D 7C 9D JC 8S]"},
+		{"actions", hand.Actions, "[{0 B small blind 1 false []} {0 C big blind 2 false []} {0 A raise 50 true []} {0 B raise 100 true []} {0 C call 98 true []} " +
+			"{4 B show 0 false [KH KS]} {4 C show 0 false [QH QS]} {4 A show 0 false [AH AS]}]"},
+		{"wins", hand.Wins, "[{A 150 main pot} {B 100 side pot-1}]"},
+		{"pot", []int{hand.TotalPot, hand.Rake}, "[250 0]"},
+	} {
+		if fmt.Sprint(test.value) != test.expected {
+			t.Errorf("%s == %v but expected %s", test.name, test.value, test.expected)
+		}
+	}
+
+	hand.ID, hand.Table, hand.Date = "1", "Engine", "2026/10/19 12:00:00 ET"
+	var written strings.Builder
+	if err := WritePokerStars(&written, []Hand{hand}); err != nil {
+		t.Fatalf("WritePokerStars err == %q but expected nil", err)
+	}
+	reread, err := ParsePokerStars(strings.NewReader(written.String()))
+	hand.Site = PokerStars
+	if err != nil || len(reread) != 1 || fmt.Sprint(reread[0]) != fmt.Sprint(hand) {
+		t.Errorf("ParsePokerStars(WritePokerStars) == %v, %v but expected %v\n%s", reread, err, hand, written.String())
+	}
+}
+
+func TestJSON(t *testing.T) {
+	h, config := allIn(t)
+	hands := []Hand{FromHoldem(h, config)}
+	hands[0].ID = "1"
+	parsed, _ := ParsePokerStars(strings.NewReader(cashHand))
+	hands = append(hands, parsed...)
+
+	var written bytes.Buffer
+	if err := WriteJSON(&written, hands); err != nil {
+		t.Fatalf("WriteJSON err == %q but expected nil", err)
+	}
+	if !strings.Contains(written.String(), `{"street":0,"player":"A","type":"raise","amount":50,"all_in":true}`) {
+		t.Errorf("WriteJSON == %s but expected A's raise all in", written.String())
+	}
+	reread, err := ParseJSON(&written)
+	if err != nil || fmt.Sprint(reread) != fmt.Sprint(hands) {
+		t.Errorf("ParseJSON(WriteJSON) == %v, %v but expected %v", reread, err, hands)
+	}
+
+	if _, err := ParseJSON(strings.NewReader("{}\n[")); err == nil || err.Error() != "Invalid hand history: line 2 unexpected end of JSON input" {
+		t.Errorf("ParseJSON([) err == %v but expected line 2 unexpected end of JSON input", err)
+	}
+}
@@ -0,0 +1,517 @@
+package history // github.com/sildani/poker-hands-go/history
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/sildani/poker-hands-go/holdem"
+	"io"
+	"math"
+	"strings"
+	"time"
+	_ "time/tzdata"
+)
+
+// OHHVersion is the Open Hand History spec version written.
+const OHHVersion = "1.4.6"
+
+// The spec's required fields, checked on import.
+var ohhRequired = []string{"spec_version", "site_name", "network_name", "internal_version", "tournament", "game_number",
+	"start_date_utc", "table_name", "game_type", "bet_limit", "table_size", "dealer_seat", "small_blind_amount",
+	"big_blind_amount", "ante_amount", "players", "rounds", "pots"}
+var ohhPlayerRequired = []string{"id", "seat", "name", "starting_stack"}
+var ohhRoundRequired = []string{"id", "street", "actions"}
+var ohhActionRequired = []string{"action_number", "player_id", "action"}
+var ohhPotRequired = []string{"number", "amount", "player_wins"}
+var ohhWinRequired = []string{"player_id", "win_amount"}
+
+type ohhFile struct {
+	OHH ohhHand `json:"ohh"`
+}
+
+type ohhHand struct {
+	SpecVersion      string         `json:"spec_version"`
+	SiteName         string         `json:"site_name"`
+	NetworkName      string         `json:"network_name"`
+	InternalVersion  string         `json:"internal_version"`
+	Tournament       bool           `json:"tournament"`
+	TournamentInfo   *ohhTournament `json:"tournament_info,omitempty"`
+	GameNumber       string         `json:"game_number"`
+	StartDateUTC     string         `json:"start_date_utc"`
+	TableName        string         `json:"table_name"`
+	GameType         string         `json:"game_type"`
+	BetLimit         ohhBetLimit    `json:"bet_limit"`
+	TableSize        int            `json:"table_size"`
+	Currency         string         `json:"currency,omitempty"`
+	DealerSeat       int            `json:"dealer_seat"`
+	SmallBlindAmount float64        `json:"small_blind_amount"`
+	BigBlindAmount   float64        `json:"big_blind_amount"`
+	AnteAmount       float64        `json:"ante_amount"`
+	HeroPlayerID     int            `json:"hero_player_id,omitempty"`
+	Flags            []string       `json:"flags"`
+	Players          []ohhPlayer    `json:"players"`
+	Rounds           []ohhRound     `json:"rounds"`
+	Pots             []ohhPot       `json:"pots"`
+}
+
+type ohhTournament struct {
+	TournamentNumber string `json:"tournament_number"`
+}
+
+type ohhBetLimit struct {
+	BetType string `json:"bet_type"`
+}
+
+type ohhPlayer struct {
+	ID            int     `json:"id"`
+	Seat          int     `json:"seat"`
+	Name          string  `json:"name"`
+	StartingStack float64 `json:"starting_stack"`
+	IsSittingOut  bool    `json:"is_sitting_out,omitempty"`
+}
+
+type ohhRound struct {
+	ID      int         `json:"id"`
+	Street  string      `json:"street"`
+	Cards   []string    `json:"cards,omitempty"`
+	Actions []ohhAction `json:"actions"`
+}
+
+type ohhAction struct {
+	ActionNumber int      `json:"action_number"`
+	PlayerID     int      `json:"player_id"`
+	Action       string   `json:"action"`
+	Amount       float64  `json:"amount,omitempty"`
+	IsAllIn      bool     `json:"is_allin"`
+	Cards        []string `json:"cards,omitempty"`
+}
+
+type ohhPot struct {
+	Number     int      `json:"number"`
+	Amount     float64  `json:"amount"`
+	Rake       float64  `json:"rake"`
+	PlayerWins []ohhWin `json:"player_wins"`
+}
+
+type ohhWin struct {
+	PlayerID  int     `json:"player_id"`
+	WinAmount float64 `json:"win_amount"`
+}
+
+// OHH names for games, betting structures, streets and actions.
+var ohhGames = map[string]string{"Hold'em": "Holdem", "Omaha": "Omaha", "Omaha Hi/Lo": "OmahaHiLo"}
+var ohhLimits = map[string]string{"No Limit": "NL", "Pot Limit": "PL", "Limit": "FL"}
+var ohhStreets = []string{"Preflop", "Flop", "Turn", "River", "Showdown"}
+var ohhActions = map[string]string{holdem.Ante: "Post Ante", holdem.SmallBlind: "Post SB", holdem.BigBlind: "Post BB",
+	BothBlinds: "Post Dead", holdem.Fold: "Fold", holdem.Check: "Check", holdem.Call: "Call", holdem.Bet: "Bet",
+	holdem.Raise: "Raise", Show: "Shows Cards", Muck: "Mucks Cards"}
+
+// dealt is the OHH action for a player's hole cards.
+const dealt = "Dealt Cards"
+
+// zones are the time zones hand history dates are given in.
+var zones = map[string]string{"ET": "America/New_York", "UTC": "UTC", "GMT": "UTC"}
+
+// WriteOHH writes the hands as Open Hand History JSON, one hand a line.
+// As in Hand, a raise's amount is the total bet on the street. Returned
+// bets are left out, as OHH has no action for them, and tournament buy-ins
+// and levels are not carried. Posting both blinds is a dead small blind
+// then the big blind, which ParseOHH puts back together.
+func WriteOHH(w io.Writer, hands []Hand) error {
+	encoder := json.NewEncoder(w)
+	for _, hand := range hands {
+		ohh, err := toOHH(hand)
+		if err != nil {
+			return err
+		}
+		if err := encoder.Encode(ohhFile{OHH: ohh}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toOHH(h Hand) (ohhHand, error) {
+	game, limit := "", ""
+	for name := range ohhGames {
+		if rest, ok := strings.CutPrefix(h.Game, name+" "); ok && ohhLimits[rest] != "" {
+			game, limit = name, rest
+		}
+	}
+	if game == "" {
+		return ohhHand{}, fmt.Errorf("Invalid hand: OHH has no game %q", h.Game)
+	}
+	date, err := utc(h.Date)
+	if err != nil {
+		return ohhHand{}, err
+	}
+
+	ohh := ohhHand{
+		SpecVersion:      OHHVersion,
+		SiteName:         h.Site,
+		NetworkName:      h.Site,
+		InternalVersion:  "1",
+		Tournament:       h.Tournament != "",
+		GameNumber:       h.ID,
+		StartDateUTC:     date,
+		TableName:        h.Table,
+		GameType:         ohhGames[game],
+		BetLimit:         ohhBetLimit{BetType: ohhLimits[limit]},
+		TableSize:        h.MaxSeats,
+		Currency:         currencies[h.Currency],
+		DealerSeat:       h.Button,
+		SmallBlindAmount: h.units(h.SmallBlind),
+		BigBlindAmount:   h.units(h.BigBlind),
+		AnteAmount:       h.units(h.Ante),
+		Flags:            []string{},
+		Players:          []ohhPlayer{},
+		Rounds:           []ohhRound{},
+		Pots:             []ohhPot{},
+	}
+	if ohh.Tournament {
+		ohh.TournamentInfo = &ohhTournament{TournamentNumber: h.Tournament}
+	}
+
+	ids := map[string]int{}
+	for i, seat := range h.Seats {
+		ids[seat.Name] = i + 1
+		ohh.Players = append(ohh.Players, ohhPlayer{ID: i + 1, Seat: seat.Number, Name: seat.Name, StartingStack: h.units(seat.Stack), IsSittingOut: seat.SittingOut})
+	}
+	ohh.HeroPlayerID = ids[h.Hero]
+
+	streets := holdem.Preflop
+	for streets < holdem.River && len(h.Board) >= 3+streets {
+		streets++
+	}
+	for _, action := range h.Actions {
+		streets = max(streets, action.Street)
+	}
+	for street := 0; street <= streets; street++ {
+		round := ohhRound{ID: street, Street: ohhStreets[street], Actions: []ohhAction{}}
+		if street >= holdem.Flop && street <= holdem.River && len(h.Board) >= street+2 {
+			round.Cards = lower(h.Board[max(0, street+1) : street+2])
+			if street == holdem.Flop {
+				round.Cards = lower(h.Board[:3])
+			}
+		}
+		ohh.Rounds = append(ohh.Rounds, round)
+	}
+
+	holes := []ohhAction{}
+	for _, seat := range h.Seats {
+		if len(seat.Hole) > 0 {
+			holes = append(holes, ohhAction{PlayerID: ids[seat.Name], Action: dealt, Cards: lower(seat.Hole)})
+		}
+	}
+	for i, action := range h.Actions {
+		if !isPost(action.Type) && holes != nil {
+			ohh.Rounds[holdem.Preflop].Actions = append(ohh.Rounds[holdem.Preflop].Actions, holes...)
+			holes = nil
+		}
+		round := &ohh.Rounds[action.Street]
+		switch action.Type {
+		case holdem.Return:
+		case BothBlinds:
+			// The small blind goes in dead and the rest is the big blind.
+			dead := min(action.Amount, h.SmallBlind)
+			round.Actions = append(round.Actions, ohhAction{PlayerID: ids[action.Player], Action: ohhActions[BothBlinds],
+				Amount: h.units(dead), IsAllIn: action.AllIn && dead == action.Amount})
+			if dead < action.Amount {
+				round.Actions = append(round.Actions, ohhAction{PlayerID: ids[action.Player], Action: ohhActions[holdem.BigBlind],
+					Amount: h.units(action.Amount - dead), IsAllIn: action.AllIn})
+			}
+		default:
+			round.Actions = append(round.Actions, ohhAction{PlayerID: ids[action.Player], Action: ohhActions[action.Type],
+				Amount: h.units(action.Amount), IsAllIn: action.AllIn, Cards: lower(action.Cards)})
+		}
+		if i == len(h.Actions)-1 && holes != nil {
+			ohh.Rounds[holdem.Preflop].Actions = append(ohh.Rounds[holdem.Preflop].Actions, holes...)
+		}
+	}
+	number := 0
+	for i := range ohh.Rounds {
+		for j := range ohh.Rounds[i].Actions {
+			number++
+			ohh.Rounds[i].Actions[j].ActionNumber = number
+		}
+	}
+
+	for _, win := range h.Wins {
+		number := 0
+		if _, side, ok := strings.Cut(win.Pot, "side pot-"); ok {
+			fmt.Sscan(side, &number)
+		}
+		for len(ohh.Pots) <= number {
+			ohh.Pots = append(ohh.Pots, ohhPot{Number: len(ohh.Pots), PlayerWins: []ohhWin{}})
+		}
+		pot := &ohh.Pots[number]
+		pot.Amount += h.units(win.Amount)
+		pot.PlayerWins = append(pot.PlayerWins, ohhWin{PlayerID: ids[win.Player], WinAmount: h.units(win.Amount)})
+	}
+	if len(ohh.Pots) > 0 {
+		ohh.Pots[0].Rake = h.units(h.Rake)
+		ohh.Pots[0].Amount += h.units(h.Rake)
+	}
+	return ohh, nil
+}
+
+// ParseOHH reads hands written as Open Hand History JSON, one after
+// another, checking the spec's required fields are there. Hands that
+// ended with a bet nobody called get the bet back, as they do in Hand.
+func ParseOHH(r io.Reader) ([]Hand, error) {
+	hands := []Hand{}
+	decoder := json.NewDecoder(r)
+	for i := 1; decoder.More(); i++ {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("Invalid OHH: hand %d %v", i, err)
+		}
+		hand, err := fromOHH(raw)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid OHH: hand %d %v", i, err)
+		}
+		hands = append(hands, hand)
+	}
+	return hands, nil
+}
+
+func fromOHH(raw json.RawMessage) (Hand, error) {
+	if err := checkOHH(raw); err != nil {
+		return Hand{}, err
+	}
+	var file ohhFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return Hand{}, err
+	}
+	ohh := file.OHH
+
+	h := Hand{Site: ohh.SiteName, ID: ohh.GameNumber, Table: ohh.TableName, MaxSeats: ohh.TableSize, Button: ohh.DealerSeat,
+		Actions: []Action{}, Wins: []Win{}}
+	h.Currency = key(currencies, ohh.Currency)
+	if ohh.Currency != "" && h.Currency == "" {
+		return Hand{}, fmt.Errorf("unknown currency %q", ohh.Currency)
+	}
+	if ohh.TournamentInfo != nil {
+		h.Tournament = ohh.TournamentInfo.TournamentNumber
+	}
+	game, limit := key(ohhGames, ohh.GameType), key(ohhLimits, ohh.BetLimit.BetType)
+	if game == "" || limit == "" {
+		return Hand{}, fmt.Errorf("unsupported game %q %q", ohh.GameType, ohh.BetLimit.BetType)
+	}
+	h.Game = game + " " + limit
+	date, err := time.Parse(time.RFC3339, ohh.StartDateUTC)
+	if err != nil {
+		return Hand{}, fmt.Errorf("invalid start_date_utc %q", ohh.StartDateUTC)
+	}
+	h.Date = date.UTC().Format("2006/01/02 15:04:05") + " UTC"
+	h.SmallBlind, h.BigBlind, h.Ante = h.amount(ohh.SmallBlindAmount), h.amount(ohh.BigBlindAmount), h.amount(ohh.AnteAmount)
+
+	names := map[int]string{}
+	for _, player := range ohh.Players {
+		names[player.ID] = player.Name
+		h.Seats = append(h.Seats, Seat{Number: player.Seat, Name: player.Name, Stack: h.amount(player.StartingStack), SittingOut: player.IsSittingOut})
+	}
+	h.Hero = names[ohh.HeroPlayerID]
+
+	for _, round := range ohh.Rounds {
+		street := indexOf(ohhStreets, round.Street)
+		if street < 0 {
+			return Hand{}, fmt.Errorf("unknown street %q", round.Street)
+		}
+		if len(round.Cards) > 0 {
+			board, err := cards(strings.Join(round.Cards, " "))
+			if err != nil {
+				return Hand{}, err
+			}
+			h.Board = append(h.Board, board...)
+		}
+		for _, action := range round.Actions {
+			name, ok := names[action.PlayerID]
+			if !ok {
+				return Hand{}, fmt.Errorf("action %d by unknown player %d", action.ActionNumber, action.PlayerID)
+			}
+			var shown []string
+			if len(action.Cards) > 0 {
+				if shown, err = cards(strings.Join(action.Cards, " ")); err != nil {
+					return Hand{}, err
+				}
+			}
+			if action.Action == dealt || action.Action == ohhActions[Show] {
+				h.Seat(name).Hole = shown
+			}
+			if action.Action == dealt {
+				continue
+			}
+
+			kind := key(ohhActions, action.Action)
+			if kind == "" {
+				return Hand{}, fmt.Errorf("action %d unknown action %q", action.ActionNumber, action.Action)
+			}
+			if last := len(h.Actions) - 1; kind == holdem.BigBlind && last >= 0 && h.Actions[last].Type == BothBlinds &&
+				h.Actions[last].Player == name && h.Actions[last].Street == street {
+				h.Actions[last].Amount += h.amount(action.Amount)
+				h.Actions[last].AllIn = action.IsAllIn
+				continue
+			}
+			h.Actions = append(h.Actions, Action{Street: street, Player: name, Type: kind, Amount: h.amount(action.Amount), AllIn: action.IsAllIn, Cards: shown})
+		}
+	}
+	h.returnUncalled()
+
+	for _, pot := range ohh.Pots {
+		h.TotalPot += h.amount(pot.Amount)
+		h.Rake += h.amount(pot.Rake)
+		for _, win := range pot.PlayerWins {
+			h.Wins = append(h.Wins, Win{Player: names[win.PlayerID], Amount: h.amount(win.WinAmount), Pot: potName(len(ohh.Pots), pot.Number)})
+		}
+	}
+	return h, nil
+}
+
+// checkOHH checks the required fields are all there.
+func checkOHH(raw json.RawMessage) error {
+	var file map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return err
+	}
+	if _, ok := file["ohh"]; !ok {
+		return fmt.Errorf("missing ohh")
+	}
+	ohh, err := required(file["ohh"], "ohh", ohhRequired)
+	if err != nil {
+		return err
+	}
+
+	checks := []struct {
+		list        string
+		fields      []string
+		inner       string
+		innerFields []string
+	}{
+		{"players", ohhPlayerRequired, "", nil},
+		{"rounds", ohhRoundRequired, "actions", ohhActionRequired},
+		{"pots", ohhPotRequired, "player_wins", ohhWinRequired},
+	}
+	for _, check := range checks {
+		var items []json.RawMessage
+		json.Unmarshal(ohh[check.list], &items)
+		for i, item := range items {
+			path := fmt.Sprintf("%s[%d]", check.list, i)
+			object, err := required(item, path, check.fields)
+			if err != nil {
+				return err
+			}
+			var inner []json.RawMessage
+			json.Unmarshal(object[check.inner], &inner)
+			for j, innerItem := range inner {
+				if _, err := required(innerItem, fmt.Sprintf("%s.%s[%d]", path, check.inner, j), check.innerFields); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// required checks the JSON object at path has the fields, and returns it.
+func required(raw json.RawMessage, path string, fields []string) (map[string]json.RawMessage, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, fmt.Errorf("%s %v", path, err)
+	}
+	for _, field := range fields {
+		if _, ok := object[field]; !ok {
+			return nil, fmt.Errorf("%s missing required field %s", path, field)
+		}
+	}
+	return object, nil
+}
+
+// returnUncalled gives back the part of the last bet nobody called, on the
+// last street with any betting, as the hand histories that record it do.
+func (h *Hand) returnUncalled() {
+	last := -1
+	for i, action := range h.Actions {
+		if action.Type != Show && action.Type != Muck && action.Type != holdem.Fold && action.Type != holdem.Check && action.Type != holdem.Ante {
+			last = i
+		}
+	}
+	if last < 0 {
+		return
+	}
+
+	street := h.Actions[last].Street
+	biggest, bet, second := "", 0, 0
+	for _, seat := range h.Seats {
+		seatBet := h.streetBet(h.Actions, seat.Name, street)
+		if seatBet > bet {
+			biggest, bet, second = seat.Name, seatBet, bet
+		} else if seatBet > second {
+			second = seatBet
+		}
+	}
+	if bet > second {
+		end := last + 1
+		for end < len(h.Actions) && h.Actions[end].Street == street {
+			end++
+		}
+		returned := Action{Street: street, Player: biggest, Type: holdem.Return, Amount: bet - second}
+		h.Actions = append(h.Actions[:end], append([]Action{returned}, h.Actions[end:]...)...)
+	}
+}
+
+// utc turns a hand history date, as in 2020/03/14 21:07:15 ET, into the
+// ISO 8601 time in UTC that OHH uses. A date with another in brackets
+// after it is taken from the one in brackets.
+func utc(date string) (string, error) {
+	if start := strings.LastIndex(date, "["); start >= 0 && strings.HasSuffix(date, "]") {
+		date = date[start+1 : len(date)-1]
+	}
+	fields := strings.Fields(date)
+	if len(fields) != 3 || zones[fields[2]] == "" {
+		return "", fmt.Errorf("Invalid hand: date %q must be as in 2020/03/14 21:07:15 ET", date)
+	}
+	location, err := time.LoadLocation(zones[fields[2]])
+	if err != nil {
+		return "", err
+	}
+	t, err := time.ParseInLocation("2006/01/02 15:04:05", fields[0]+" "+fields[1], location)
+	if err != nil {
+		return "", fmt.Errorf("Invalid hand: date %q must be as in 2020/03/14 21:07:15 ET", date)
+	}
+	return t.UTC().Format(time.RFC3339), nil
+}
+
+// units is an amount in chips or whole currency units, as OHH has them.
+func (h Hand) units(amount int) float64 {
+	if h.Currency == "" {
+		return float64(amount)
+	}
+	return float64(amount) / 100
+}
+
+// amount is an OHH amount in chips, or cents when the hand has a currency.
+func (h Hand) amount(units float64) int {
+	if h.Currency == "" {
+		return int(math.Round(units))
+	}
+	return int(math.Round(units * 100))
+}
+
+// key finds the key for value in values, "" if there is none.
+func key(values map[string]string, value string) string {
+	for k, v := range values {
+		if v == value && value != "" {
+			return k
+		}
+	}
+	return ""
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
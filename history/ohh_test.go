@@ -0,0 +1,93 @@
+package history // github.com/sildani/poker-hands-go/history
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestOHH(t *testing.T) {
+	hands, _ := ParsePokerStars(strings.NewReader(cashHand + "\n\n" + tournamentHand))
+	h, config := allIn(t)
+	engine := FromHoldem(h, config)
+	engine.ID, engine.Table, engine.Date = "1", "Engine", "2026/10/19 12:00:00 UTC"
+	hands = append(hands, engine)
+
+	// Sitter comes back in, posting both blinds, and folds.
+	dead := hands[0]
+	dead.ID = "210987654322"
+	dead.Seats = append([]Seat{}, dead.Seats...)
+	dead.Seats[1].SittingOut = false
+	dead.Actions = append(append(append([]Action{}, dead.Actions[:2]...),
+		Action{Player: "Sitter", Type: BothBlinds, Amount: 3}, Action{Player: "Sitter", Type: "fold"}), dead.Actions[2:]...)
+	hands = append(hands, dead)
+
+	var written bytes.Buffer
+	if err := WriteOHH(&written, hands); err != nil {
+		t.Fatalf("WriteOHH err == %q but expected nil", err)
+	}
+	for _, expected := range []string{
+		`{"ohh":{"spec_version":"1.4.6","site_name":"PokerStars","network_name":"PokerStars","internal_version":"1","tournament":false,"game_number":"210987654321","start_date_utc":"2020-03-15T01:07:15Z","table_name":"Alcyone II","game_type":"Holdem","bet_limit":{"bet_type":"NL"},"table_size":6,"currency":"USD","dealer_seat":3,"small_blind_amount":0.01,"big_blind_amount":0.02,"ante_amount":0,"hero_player_id":3,`,
+		`{"id":1,"street":"Flop","cards":["2c","3d","Kh"],"actions":[{"action_number":8,"player_id":4,"action":"Check","is_allin":false},`,
+		`{"action_number":11,"player_id":3,"action":"Raise","amount":1.94,"is_allin":true}`,
+		`{"action_number":3,"player_id":3,"action":"Dealt Cards","is_allin":false,"cards":["Ah","Kd"]}`,
+		`"pots":[{"number":0,"amount":3.02,"rake":0.11,"player_wins":[{"player_id":3,"win_amount":2.91}]}]`,
+		`"tournament":true,"tournament_info":{"tournament_number":"3001"}`,
+		`{"ohh":{"spec_version":"1.4.6","site_name":"poker-hands-go","network_name":"poker-hands-go",`,
+		`{"action_number":3,"player_id":2,"action":"Post Dead","amount":0.01,"is_allin":false},{"action_number":4,"player_id":2,"action":"Post BB","amount":0.02,"is_allin":false}`,
+		`"pots":[{"number":0,"amount":150,"rake":0,"player_wins":[{"player_id":1,"win_amount":150}]},{"number":1,"amount":100,"rake":0,"player_wins":[{"player_id":2,"win_amount":100}]}]`,
+	} {
+		if !strings.Contains(written.String(), expected) {
+			t.Errorf("WriteOHH == %s but expected it to contain %s", written.String(), expected)
+		}
+	}
+
+	reread, err := ParseOHH(&written)
+	if err != nil {
+		t.Fatalf("ParseOHH err == %q but expected nil", err)
+	}
+	hands[0].Date, hands[3].Date = "2020/03/15 01:07:15 UTC", "2020/03/15 01:07:15 UTC"
+	hands[1].Date, hands[1].BuyIn, hands[1].Level = "2020/03/15 01:10:00 UTC", "", ""
+	for i := range hands {
+		if fmt.Sprint(reread[i]) != fmt.Sprint(hands[i]) {
+			t.Errorf("ParseOHH(WriteOHH) == %v but expected %v", reread[i], hands[i])
+		}
+	}
+}
+
+func TestParseOHHErrors(t *testing.T) {
+	hands, _ := ParsePokerStars(strings.NewReader(tournamentHand))
+	var written bytes.Buffer
+	WriteOHH(&written, hands)
+	valid := written.String()
+
+	for _, test := range []struct {
+		from        string
+		to          string
+		expectedErr string
+	}{
+		{`"game_number":"2",`, ``, "Invalid OHH: hand 1 ohh missing required field game_number"},
+		{`{"ohh":`, `{"hand":`, "Invalid OHH: hand 1 missing ohh"},
+		{`"name":"B",`, ``, "Invalid OHH: hand 1 players[1] missing required field name"},
+		{`"action_number":2,`, ``, "Invalid OHH: hand 1 rounds[0].actions[1] missing required field action_number"},
+		{`{"player_id":2,"win_amount":40}`, `{"player_id":2}`, "Invalid OHH: hand 1 pots[0].player_wins[0] missing required field win_amount"},
+		{`"Fold"`, `"Dance"`, `Invalid OHH: hand 1 action 5 unknown action "Dance"`},
+		{`"game_type":"Holdem"`, `"game_type":"Razz"`, `Invalid OHH: hand 1 unsupported game "Razz" "NL"`},
+		{`"start_date_utc":"2020-03-15T01:10:00Z"`, `"start_date_utc":"yesterday"`, `Invalid OHH: hand 1 invalid start_date_utc "yesterday"`},
+		{`}}`, `}`, "Invalid OHH: hand 1 unexpected EOF"},
+	} {
+		input := strings.Replace(valid, test.from, test.to, 1)
+		if input == valid {
+			t.Fatalf("%q is not in %s", test.from, valid)
+		}
+		if _, err := ParseOHH(strings.NewReader(input)); err == nil || err.Error() != test.expectedErr {
+			t.Errorf("ParseOHH err == %v but expected %q", err, test.expectedErr)
+		}
+	}
+
+	hands[0].Date = "14 March 2020"
+	if err := WriteOHH(&written, hands); err == nil || err.Error() != `Invalid hand: date "14 March 2020" must be as in 2020/03/14 21:07:15 ET` {
+		t.Errorf("WriteOHH err == %v but expected the date refused", err)
+	}
+}
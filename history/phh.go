@@ -0,0 +1,598 @@
+package history // github.com/sildani/poker-hands-go/history
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/sildani/poker-hands-go/betting"
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/holdem"
+	"github.com/sildani/poker-hands-go/parser"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// PHH is a hand in the Poker Hand History format. Players are listed from
+// the seat after the button round to the button, so heads up the button is
+// p2 and posts the small blind, and Antes, BlindsOrStraddles and the stacks
+// go by player. Actions are
+// the dealer's, as in "d dh p1 AcKs" and "d db Jc3d5c", and the players',
+// as in "p1 f", "p2 cc", "p3 cbr 300" to bet or raise to 300 on the street
+// and "p1 sm AcKs" to show, or "p1 sm" to muck. SmallBet and BigBet are
+// the fixed-limit bets, zero in other games.
+type PHH struct {
+	Variant           string
+	Antes             []int
+	BlindsOrStraddles []int
+	MinBet            int
+	SmallBet          int
+	BigBet            int
+	StartingStacks    []int
+	Actions           []string
+	Players           []string
+	FinishingStacks   []int
+}
+
+// phhVariants are the PHH codes for the holdem engine's games, by
+// betting structure and game.
+var phhVariants = map[string][2]string{
+	"NT": {betting.NoLimit, holdem.Holdem}, "PT": {betting.PotLimit, holdem.Holdem}, "FT": {betting.FixedLimit, holdem.Holdem},
+	"NO": {betting.NoLimit, holdem.Omaha}, "PO": {betting.PotLimit, holdem.Omaha}, "FO": {betting.FixedLimit, holdem.Omaha},
+	"NO/8": {betting.NoLimit, holdem.OmahaHiLo}, "PO/8": {betting.PotLimit, holdem.OmahaHiLo}, "FO/8": {betting.FixedLimit, holdem.OmahaHiLo},
+}
+
+// ParsePHH reads a hand written in PHH, a subset of TOML: one key to a
+// line, arrays of numbers or strings that may run over lines, and
+// comments. Keys other than PHH's fields are skipped.
+func ParsePHH(r io.Reader) (PHH, error) {
+	var p PHH
+	scanner := bufio.NewScanner(r)
+	line, start, text := 0, 0, ""
+	for scanner.Scan() {
+		line++
+		text += " " + uncomment(scanner.Text())
+		if start == 0 {
+			start = line
+		}
+		if strings.TrimSpace(text) == "" || depth(text) > 0 {
+			if strings.TrimSpace(text) == "" {
+				text, start = "", 0
+			}
+			continue
+		}
+		if err := p.field(text); err != nil {
+			return PHH{}, fmt.Errorf("Invalid PHH: line %d %v", start, err)
+		}
+		text, start = "", 0
+	}
+	if err := scanner.Err(); err != nil {
+		return PHH{}, err
+	}
+	if strings.TrimSpace(text) != "" {
+		return PHH{}, fmt.Errorf("Invalid PHH: line %d array is not closed", start)
+	}
+	if p.Variant == "" || p.StartingStacks == nil || p.Actions == nil {
+		return PHH{}, errors.New("Invalid PHH: must have variant, starting_stacks and actions")
+	}
+	return p, nil
+}
+
+// inString is, for each byte of text, whether it is part of a string: a
+// 'literal' string, or a "basic" one where a backslash escapes the next
+// character.
+func inString(text string) []bool {
+	inside := make([]bool, len(text))
+	quote := byte(0)
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote == '"' && c == '\\' && i+1 < len(text):
+			inside[i], inside[i+1] = true, true
+			i++
+			continue
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '\'' || c == '"'):
+			quote = c
+		case quote == 0:
+			continue
+		}
+		inside[i] = true
+	}
+	return inside
+}
+
+// uncomment drops a # comment that is not in a string.
+func uncomment(line string) string {
+	inside := inString(line)
+	for i := range line {
+		if line[i] == '#' && !inside[i] {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// depth is how many arrays text opens and does not close, outside strings.
+func depth(text string) int {
+	inside := inString(text)
+	open := 0
+	for i := range text {
+		if text[i] == '[' && !inside[i] {
+			open++
+		} else if text[i] == ']' && !inside[i] {
+			open--
+		}
+	}
+	return open
+}
+
+func (p *PHH) field(text string) error {
+	key, value, ok := strings.Cut(text, "=")
+	if !ok {
+		return fmt.Errorf("expected key = value, not %q", strings.TrimSpace(text))
+	}
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+	var err error
+	switch key {
+	case "variant":
+		p.Variant, err = phhString(value)
+	case "antes":
+		p.Antes, err = phhInts(value)
+	case "blinds_or_straddles":
+		p.BlindsOrStraddles, err = phhInts(value)
+	case "min_bet":
+		p.MinBet, err = phhInt(value)
+	case "small_bet":
+		p.SmallBet, err = phhInt(value)
+	case "big_bet":
+		p.BigBet, err = phhInt(value)
+	case "starting_stacks":
+		p.StartingStacks, err = phhInts(value)
+	case "finishing_stacks":
+		p.FinishingStacks, err = phhInts(value)
+	case "actions":
+		p.Actions, err = phhStrings(value)
+	case "players":
+		p.Players, err = phhStrings(value)
+	}
+	if err != nil {
+		return fmt.Errorf("%s %v", key, err)
+	}
+	return nil
+}
+
+func phhString(value string) (string, error) {
+	if len(value) < 2 || (value[0] != '\'' && value[0] != '"') || value[len(value)-1] != value[0] {
+		return "", fmt.Errorf("expected a string, not %s", value)
+	}
+	if value[0] == '\'' {
+		return value[1 : len(value)-1], nil
+	}
+	s, err := strconv.Unquote(value)
+	if err != nil {
+		return "", fmt.Errorf("expected a string, not %s", value)
+	}
+	return s, nil
+}
+
+// phhQuote writes s as a 'literal' string, or as a "basic" one with
+// escapes when it has a quote or control character a literal cannot hold.
+func phhQuote(s string) string {
+	if !strings.ContainsFunc(s, func(c rune) bool { return c == '\'' || c < ' ' || c == 0x7f }) {
+		return "'" + s + "'"
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, c := range s {
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(c)
+		case c < ' ' || c == 0x7f:
+			fmt.Fprintf(&b, "\\u%04X", c)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func phhInt(value string) (int, error) {
+	number, err := strconv.Atoi(strings.ReplaceAll(value, "_", ""))
+	if err != nil {
+		return 0, fmt.Errorf("expected a whole number, not %s", value)
+	}
+	return number, nil
+}
+
+// phhArray splits an array into its values at the commas outside strings.
+func phhArray(value string) ([]string, error) {
+	if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+		return nil, fmt.Errorf("expected an array, not %s", value)
+	}
+	values := []string{}
+	items := value[1 : len(value)-1]
+	inside := inString(items)
+	start := 0
+	for i := 0; i <= len(items); i++ {
+		if i < len(items) && (items[i] != ',' || inside[i]) {
+			continue
+		}
+		if item := strings.TrimSpace(items[start:i]); item != "" {
+			values = append(values, item)
+		}
+		start = i + 1
+	}
+	return values, nil
+}
+
+func phhInts(value string) ([]int, error) {
+	items, err := phhArray(value)
+	numbers := []int{}
+	for _, item := range items {
+		number, err := phhInt(item)
+		if err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers, err
+}
+
+func phhStrings(value string) ([]string, error) {
+	items, err := phhArray(value)
+	strs := []string{}
+	for _, item := range items {
+		s, err := phhString(item)
+		if err != nil {
+			return nil, err
+		}
+		strs = append(strs, s)
+	}
+	return strs, err
+}
+
+// WritePHH writes the hand in PHH.
+func WritePHH(w io.Writer, p PHH) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "variant = %s\n", phhQuote(p.Variant))
+	fmt.Fprintf(&b, "antes = %s\n", phhList(p.Antes))
+	fmt.Fprintf(&b, "blinds_or_straddles = %s\n", phhList(p.BlindsOrStraddles))
+	fmt.Fprintf(&b, "min_bet = %d\n", p.MinBet)
+	if p.SmallBet != 0 || p.BigBet != 0 {
+		fmt.Fprintf(&b, "small_bet = %d\nbig_bet = %d\n", p.SmallBet, p.BigBet)
+	}
+	fmt.Fprintf(&b, "starting_stacks = %s\n", phhList(p.StartingStacks))
+	b.WriteString("actions = [\n")
+	for _, action := range p.Actions {
+		fmt.Fprintf(&b, "  %s,\n", phhQuote(action))
+	}
+	b.WriteString("]\n")
+	if p.Players != nil {
+		names := []string{}
+		for _, name := range p.Players {
+			names = append(names, phhQuote(name))
+		}
+		fmt.Fprintf(&b, "players = [%s]\n", strings.Join(names, ", "))
+	}
+	if p.FinishingStacks != nil {
+		fmt.Fprintf(&b, "finishing_stacks = %s\n", phhList(p.FinishingStacks))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func phhList(numbers []int) string {
+	items := []string{}
+	for _, number := range numbers {
+		items = append(items, strconv.Itoa(number))
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// PHH writes the hand in PHH, amounts in its chips or cents. Only the
+// players dealt in are listed, and hole cards nobody saw are written ??.
+func (h Hand) PHH() (PHH, error) {
+	variant := ""
+	for code, game := range phhVariants {
+		if games[game[1]]+" "+limits[game[0]] == h.Game {
+			variant = code
+		}
+	}
+	if variant == "" {
+		return PHH{}, fmt.Errorf("Invalid hand: PHH has no variant for %q", h.Game)
+	}
+
+	button := 0
+	seats := []Seat{}
+	for i, seat := range h.Seats {
+		if seat.Number == h.Button {
+			button = i
+		}
+	}
+	for i := range h.Seats {
+		if seat := h.Seats[(button+1+i)%len(h.Seats)]; !seat.SittingOut {
+			seats = append(seats, seat)
+		}
+	}
+
+	p := PHH{Variant: variant, MinBet: h.BigBlind, Actions: []string{}, Players: []string{}}
+	if phhVariants[variant][0] == betting.FixedLimit {
+		p.SmallBet, p.BigBet = h.BigBlind, 2*h.BigBlind
+	}
+	players := map[string]string{}
+	for i, seat := range seats {
+		players[seat.Name] = fmt.Sprintf("p%d", i+1)
+		p.Players = append(p.Players, seat.Name)
+		p.StartingStacks = append(p.StartingStacks, seat.Stack)
+		p.Antes = append(p.Antes, 0)
+		p.BlindsOrStraddles = append(p.BlindsOrStraddles, 0)
+		p.FinishingStacks = append(p.FinishingStacks, seat.Stack)
+	}
+
+	holes := 2
+	if strings.HasPrefix(h.Game, "Omaha") {
+		holes = 4
+	}
+	for i, seat := range seats {
+		hole := strings.Repeat("??", holes)
+		if len(seat.Hole) > 0 {
+			hole = strings.Join(lower(seat.Hole), "")
+		}
+		p.Actions = append(p.Actions, fmt.Sprintf("d dh p%d %s", i+1, hole))
+	}
+
+	street := holdem.Preflop
+	bounds := []int{0, 3, 4, 5}
+	deal := func(to int) {
+		for ; street < min(to, holdem.River) && len(h.Board) >= bounds[street+1]; street++ {
+			p.Actions = append(p.Actions, "d db "+strings.Join(lower(h.Board[bounds[street]:bounds[street+1]]), ""))
+		}
+	}
+	for i, action := range h.Actions {
+		player, ok := players[action.Player]
+		if !ok {
+			return PHH{}, fmt.Errorf("Invalid hand: %s acts but is not dealt in", action.Player)
+		}
+		seat := indexOf(p.Players, action.Player)
+
+		switch action.Type {
+		case holdem.Ante:
+			p.Antes[seat] = action.Amount
+			p.FinishingStacks[seat] -= action.Amount
+		case holdem.SmallBlind, holdem.BigBlind:
+			p.BlindsOrStraddles[seat] = action.Amount
+			p.FinishingStacks[seat] -= action.Amount
+		case BothBlinds:
+			return PHH{}, errors.New("Invalid hand: PHH has no dead blinds")
+		case holdem.Return:
+			p.FinishingStacks[seat] += action.Amount
+		case holdem.Bet, holdem.Raise:
+			p.FinishingStacks[seat] -= action.Amount - h.streetBet(h.Actions[:i], action.Player, action.Street)
+		default:
+			p.FinishingStacks[seat] -= action.Amount
+		}
+
+		deal(action.Street)
+		switch action.Type {
+		case holdem.Fold:
+			p.Actions = append(p.Actions, player+" f")
+		case holdem.Check, holdem.Call:
+			p.Actions = append(p.Actions, player+" cc")
+		case holdem.Bet, holdem.Raise:
+			p.Actions = append(p.Actions, fmt.Sprintf("%s cbr %d", player, action.Amount))
+		case Show:
+			p.Actions = append(p.Actions, player+" sm "+strings.Join(lower(action.Cards), ""))
+		case Muck:
+			p.Actions = append(p.Actions, player+" sm")
+		}
+	}
+	deal(holdem.River)
+
+	for _, win := range h.Wins {
+		p.FinishingStacks[indexOf(p.Players, win.Player)] += win.Amount
+	}
+	return p, nil
+}
+
+// Replay plays the hand through the holdem engine, dealing the cards the
+// actions show and any others from what is left, and checks everyone ends
+// with their finishing stacks, if given.
+func (p PHH) Replay() (*holdem.Hand, error) {
+	game, ok := phhVariants[p.Variant]
+	if !ok {
+		return nil, fmt.Errorf("Invalid PHH: unsupported variant %q", p.Variant)
+	}
+	n := len(p.StartingStacks)
+	if n < 2 || len(p.BlindsOrStraddles) != n || (len(p.Antes) != n && len(p.Antes) != 0) {
+		return nil, errors.New("Invalid PHH: must have blinds and antes for every player")
+	}
+	config := holdem.Config{Variant: game[1], Limit: game[0], SmallBlind: p.BlindsOrStraddles[0], BigBlind: p.BlindsOrStraddles[1]}
+	if n == 2 {
+		config.SmallBlind, config.BigBlind = min(config.SmallBlind, config.BigBlind), max(config.SmallBlind, config.BigBlind)
+	}
+	for _, blind := range p.BlindsOrStraddles[2:] {
+		if blind != 0 {
+			return nil, errors.New("Invalid PHH: straddles are not supported")
+		}
+	}
+	if config.Limit == betting.FixedLimit && (p.SmallBet != config.BigBlind || p.BigBet != 2*config.BigBlind) {
+		return nil, fmt.Errorf("Invalid PHH: fixed-limit bets must be the big blind and twice that, not small_bet %d and big_bet %d",
+			p.SmallBet, p.BigBet)
+	}
+	for _, ante := range p.Antes {
+		if ante != p.Antes[0] {
+			return nil, errors.New("Invalid PHH: antes must be the same for every player")
+		}
+		config.Ante = ante
+	}
+
+	holes := make([][]string, n)
+	board := []string{}
+	for _, action := range p.Actions {
+		fields := strings.Fields(action)
+		if len(fields) == 4 && fields[0] == "d" && fields[1] == "dh" {
+			if seat, err := strconv.Atoi(strings.TrimPrefix(fields[2], "p")); err == nil && seat >= 1 && seat <= n {
+				holes[seat-1] = phhCards(fields[3])
+			}
+		}
+		if len(fields) == 3 && fields[0] == "d" && fields[1] == "db" {
+			board = append(board, phhCards(fields[2])...)
+		}
+	}
+	d, err := phhDeck(holes, board, n-1)
+	if err != nil {
+		return nil, err
+	}
+
+	seats := []holdem.Seat{}
+	for i, stack := range p.StartingStacks {
+		name := fmt.Sprintf("p%d", i+1)
+		if i < len(p.Players) {
+			name = p.Players[i]
+		}
+		seats = append(seats, holdem.Seat{Name: name, Stack: stack})
+	}
+	h, err := holdem.NewHand(config, seats, n-1, d)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid PHH: %v", err)
+	}
+
+	for i, action := range p.Actions {
+		fields := strings.Fields(action)
+		if len(fields) == 0 || fields[0] == "d" {
+			continue
+		}
+		if err := p.act(h, fields); err != nil {
+			return nil, fmt.Errorf("Invalid PHH: action %d %q %v", i+1, action, err)
+		}
+	}
+	if !h.Done() {
+		return nil, errors.New("Invalid PHH: the actions end before the hand does")
+	}
+
+	stacks := []int{}
+	for _, player := range h.Players() {
+		stacks = append(stacks, player.Stack)
+	}
+	if p.FinishingStacks != nil && fmt.Sprint(stacks) != fmt.Sprint(p.FinishingStacks) {
+		return h, fmt.Errorf("Invalid PHH: finishing stacks %v but the engine ends with %v", p.FinishingStacks, stacks)
+	}
+	return h, nil
+}
+
+func (p PHH) act(h *holdem.Hand, fields []string) error {
+	seat, err := strconv.Atoi(strings.TrimPrefix(fields[0], "p"))
+	if err != nil || !strings.HasPrefix(fields[0], "p") {
+		return errors.New("is not by the dealer or a player")
+	}
+	if len(fields) >= 2 && fields[1] == "sm" {
+		return nil
+	}
+	if h.ToAct() != seat-1 {
+		return fmt.Errorf("but p%d is to act", h.ToAct()+1)
+	}
+
+	action := holdem.Action{}
+	switch {
+	case len(fields) == 2 && fields[1] == "f":
+		action.Type = holdem.Fold
+	case len(fields) == 2 && fields[1] == "cc" && h.Options().Check:
+		action.Type = holdem.Check
+	case len(fields) == 2 && fields[1] == "cc":
+		action.Type = holdem.Call
+	case len(fields) == 3 && fields[1] == "cbr":
+		action.Type = holdem.Raise
+		if h.CurrentBet() == 0 {
+			action.Type = holdem.Bet
+		}
+		if action.Amount, err = strconv.Atoi(fields[2]); err != nil {
+			return fmt.Errorf("amount must be a whole number")
+		}
+	default:
+		return errors.New("is not f, cc, cbr or sm")
+	}
+	return h.Act(action)
+}
+
+// phhCards reads cards run together, as in AcKs, with ?? for a card
+// nobody saw.
+func phhCards(s string) []string {
+	cards := []string{}
+	for i := 0; i+1 < len(s); i += 2 {
+		cards = append(cards, strings.ToUpper(s[i:i+2]))
+	}
+	return cards
+}
+
+// phhDeck stacks a deck to deal the holes and board in the engine's order,
+// from the seat after the button, with unknown cards, burns and anything
+// not dealt taken from the cards left over.
+func phhDeck(holes [][]string, board []string, button int) (*deck.Deck, error) {
+	used := map[string]bool{}
+	for _, card := range append(append([]string{}, board...), flatten(holes)...) {
+		if card != "??" {
+			if used[card] {
+				return nil, fmt.Errorf("Invalid PHH: %s is dealt twice", card)
+			}
+			used[card] = true
+		}
+	}
+	spare := []string{}
+	for _, card := range parser.Cards() {
+		if !used[card] {
+			spare = append(spare, card)
+		}
+	}
+	next := func(card string) string {
+		if card != "??" && card != "" {
+			return card
+		}
+		card, spare = spare[0], spare[1:]
+		return card
+	}
+
+	count := 0
+	for _, hole := range holes {
+		count = max(count, len(hole))
+	}
+	n := len(holes)
+	first := (button + 1) % n
+	if n == 2 {
+		first = button
+	}
+	cards := []string{}
+	for round := 0; round < count; round++ {
+		for i := 0; i < n; i++ {
+			hole := holes[(first+i)%n]
+			card := ""
+			if round < len(hole) {
+				card = hole[round]
+			}
+			cards = append(cards, next(card))
+		}
+	}
+	for i := 0; i < 5; i++ {
+		if i == 0 || i == 3 || i == 4 {
+			cards = append(cards, next(""))
+		}
+		card := ""
+		if i < len(board) {
+			card = board[i]
+		}
+		cards = append(cards, next(card))
+	}
+	return deck.NewStacked(cards)
+}
+
+func flatten(lists [][]string) []string {
+	flat := []string{}
+	for _, list := range lists {
+		flat = append(flat, list...)
+	}
+	return flat
+}
@@ -0,0 +1,166 @@
+package history // github.com/sildani/poker-hands-go/history
+
+import (
+	"fmt"
+	"github.com/sildani/poker-hands-go/betting"
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/holdem"
+	"strings"
+	"testing"
+)
+
+const phhHand = `# Three handed, Alice takes it on the flop
+variant = 'NT'
+ante_trimming_status = true
+antes = [0, 0, 0]
+blinds_or_straddles = [1, 2, 0]
+min_bet = 2
+starting_stacks = [200, 200, 200]
+actions = [
+  'd dh p1 AcKs',
+  'd dh p2 ????',
+  'd dh p3 7h7d',
+  'p3 cbr 6',
+  'p1 cbr 20',
+  'p2 f',
+  'p3 cc',
+  'd db Jc3d5c',  # the flop
+  'p1 cbr 20',
+  'p3 f',
+]
+players = ['Alice', 'Bob', 'Carol']
+finishing_stacks = [222, 198, 180]
+`
+
+func TestParsePHH(t *testing.T) {
+	p, err := ParsePHH(strings.NewReader(phhHand))
+	if err != nil {
+		t.Fatalf("ParsePHH err == %q but expected nil", err)
+	}
+	expected := "{NT [0 0 0] [1 2 0] 2 0 0 [200 200 200] [d dh p1 AcKs d dh p2 ???? d dh p3 7h7d p3 cbr 6 p1 cbr 20 p2 f p3 cc d db Jc3d5c p1 cbr 20 p3 f] [Alice Bob Carol] [222 198 180]}"
+	if fmt.Sprint(p) != expected {
+		t.Errorf("ParsePHH == %v but expected %s", p, expected)
+	}
+
+	h, err := p.Replay()
+	if err != nil {
+		t.Fatalf("Replay err == %q but expected nil", err)
+	}
+	if fmt.Sprint(h.Players()[0].Hole, h.Board()[:3]) != "[AC KS] [JC 3D 5C]" {
+		t.Errorf("Replay dealt %v and %v but expected AC KS and JC 3D 5C", h.Players()[0].Hole, h.Board())
+	}
+
+	var written strings.Builder
+	WritePHH(&written, p)
+	if reread, err := ParsePHH(strings.NewReader(written.String())); err != nil || fmt.Sprint(reread) != expected {
+		t.Errorf("ParsePHH(WritePHH) == %v, %v but expected %s", reread, err, expected)
+	}
+}
+
+func TestPHHQuotedNames(t *testing.T) {
+	p, _ := ParsePHH(strings.NewReader(phhHand))
+	p.Players = []string{"O'Brien", "Smith, J", `The "[Kid]" # \ 1`}
+
+	var written strings.Builder
+	WritePHH(&written, p)
+	if !strings.Contains(written.String(), `players = ["O'Brien", 'Smith, J', 'The "[Kid]" # \ 1']`) {
+		t.Errorf("WritePHH == %s but expected the names quoted so TOML reads them back", written.String())
+	}
+	reread, err := ParsePHH(strings.NewReader(written.String()))
+	if err != nil || len(reread.Players) != 3 || fmt.Sprint(reread) != fmt.Sprint(p) {
+		t.Errorf("ParsePHH(WritePHH) players == %q, %v but expected %q", reread.Players, err, p.Players)
+	}
+
+	reread, err = ParsePHH(strings.NewReader(`variant = "N\u0054"
+starting_stacks = [200, 200]
+actions = ["p1 f"]  # "quoted, \"escaped\" [
+players = ["A \"B\", C", 'D']`))
+	if err != nil || reread.Variant != "NT" || fmt.Sprintf("%q", reread.Players) != `["A \"B\", C" "D"]` {
+		t.Errorf("ParsePHH(basic strings) == %v, %v but expected NT and two players", reread, err)
+	}
+}
+
+func TestHandPHH(t *testing.T) {
+	h, config := allIn(t)
+	p, err := FromHoldem(h, config).PHH()
+	if err != nil {
+		t.Fatalf("PHH err == %q but expected nil", err)
+	}
+	expected := "{NT [0 0 0] [1 2 0] 2 0 0 [100 100 50] [d dh p1 KhKs d dh p2 QhQs d dh p3 AhAs p3 cbr 50 p1 cbr 100 p2 cc d db 3d7c9d d db Jc d db 8s p1 sm KhKs p2 sm QhQs p3 sm AhAs] [B C A] [100 0 150]}"
+	if fmt.Sprint(p) != expected {
+		t.Errorf("PHH == %v but expected %s", p, expected)
+	}
+	if _, err := p.Replay(); err != nil {
+		t.Errorf("Replay err == %q but expected the finishing stacks to match", err)
+	}
+
+	// A fixed-limit hand carries its bets through to the replay.
+	config = holdem.Config{SmallBlind: 1, BigBlind: 2, Limit: betting.FixedLimit}
+	seats := []holdem.Seat{{Name: "A", Stack: 50}, {Name: "B", Stack: 100}, {Name: "C", Stack: 100}}
+	d, _ := deck.NewStacked(nil)
+	limit, _ := holdem.NewHand(config, seats, 0, d)
+	for !limit.Done() {
+		action := holdem.Action{Type: holdem.Check}
+		if options := limit.Options(); !options.Check {
+			action = holdem.Action{Type: holdem.Call}
+		} else if limit.Street() == holdem.Turn && limit.CurrentBet() == 0 {
+			action = holdem.Action{Type: holdem.Bet, Amount: options.MinRaise}
+		}
+		if err := limit.Act(action); err != nil {
+			t.Fatalf("fixed-limit Act(%+v) err == %q but expected nil", action, err)
+		}
+	}
+	p, _ = FromHoldem(limit, config).PHH()
+	var written strings.Builder
+	WritePHH(&written, p)
+	reread, _ := ParsePHH(strings.NewReader(written.String()))
+	if _, err := reread.Replay(); err != nil || reread.SmallBet != 2 || reread.BigBet != 4 {
+		t.Errorf("fixed-limit Replay err == %v with small_bet %d and big_bet %d but expected 2 and 4", err, reread.SmallBet, reread.BigBet)
+	}
+
+	hands, _ := ParsePokerStars(strings.NewReader(tournamentHand + "\n\n" + cashHand))
+	p, _ = hands[0].PHH()
+	if fmt.Sprint(p.Players, p.Antes, p.BlindsOrStraddles, p.FinishingStacks) != "[B A] [5 5] [30 15] [1520 1480]" {
+		t.Errorf("heads up PHH == %v but expected the button A as p2 on the small blind", p)
+	}
+	if _, err := p.Replay(); err != nil {
+		t.Errorf("heads up Replay err == %q but expected the finishing stacks to match", err)
+	}
+
+	// The engine takes no rake, so the cash hand ends 11 cents apart.
+	p, _ = hands[1].PHH()
+	if _, err := p.Replay(); err == nil || err.Error() != "Invalid PHH: finishing stacks [0 211 341] but the engine ends with [0 211 352]" {
+		t.Errorf("raked Replay err == %v but expected the rake to show", err)
+	}
+}
+
+func TestPHHErrors(t *testing.T) {
+	for _, test := range []struct {
+		from        string
+		to          string
+		expectedErr string
+	}{
+		{"variant = 'NT'", "variant = 'F7S'", `Invalid PHH: unsupported variant "F7S"`},
+		{"min_bet = 2", "min_bet = two", "Invalid PHH: line 6 min_bet expected a whole number, not two"},
+		{"min_bet = 2", "min_bet 2", `Invalid PHH: line 6 expected key = value, not "min_bet 2"`},
+		{"players = ['Alice', 'Bob', 'Carol']", "players = ['Alice', 'Bob', 'Carol'", "Invalid PHH: line 20 array is not closed"},
+		{"'p3 cbr 6'", "'p1 cbr 6'", `Invalid PHH: action 4 "p1 cbr 6" but p3 is to act`},
+		{"'p3 cbr 6'", "'p3 cbr 1'", `Invalid PHH: action 4 "p3 cbr 1" Invalid action: raise must be from 4 to 200`},
+		{"'p3 cbr 6'", "'p3 raise 6'", `Invalid PHH: action 4 "p3 raise 6" is not f, cc, cbr or sm`},
+		{"'d dh p3 7h7d'", "'d dh p3 AcKs'", "Invalid PHH: AC is dealt twice"},
+		{"  'p3 f',\n", "", "Invalid PHH: the actions end before the hand does"},
+		{"[222, 198, 180]", "[220, 200, 180]", "Invalid PHH: finishing stacks [220 200 180] but the engine ends with [222 198 180]"},
+		{"[1, 2, 0]", "[1, 2, 4]", "Invalid PHH: straddles are not supported"},
+		{"variant = 'NT'", "variant = 'FT'\nsmall_bet = 4\nbig_bet = 8", "Invalid PHH: fixed-limit bets must be the big blind and twice that, not small_bet 4 and big_bet 8"},
+		{"variant = 'NT'", "variant = 'FT'", "Invalid PHH: fixed-limit bets must be the big blind and twice that, not small_bet 0 and big_bet 0"},
+	} {
+		input := strings.Replace(phhHand, test.from, test.to, 1)
+		p, err := ParsePHH(strings.NewReader(input))
+		if err == nil {
+			_, err = p.Replay()
+		}
+		if err == nil || err.Error() != test.expectedErr {
+			t.Errorf("ParsePHH and Replay err == %v but expected %q", err, test.expectedErr)
+		}
+	}
+}
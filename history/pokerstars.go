@@ -0,0 +1,586 @@
+package history // github.com/sildani/poker-hands-go/history
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/sildani/poker-hands-go/holdem"
+	"github.com/sildani/poker-hands-go/parser"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const PokerStars = "PokerStars"
+
+var idPattern = regexp.MustCompile(`^\d+$`)
+var headerPattern = regexp.MustCompile(`^PokerStars (?:Zoom )?(?:Home Game )?Hand #(\d+): +(.+?) - (\d{4}/\d\d/\d\d .+)$`)
+var gamePattern = regexp.MustCompile(`^(?:Tournament #(\d+), (?:(.+) )?)?((?:Hold'em|Omaha|Omaha Hi/Lo) (?:No Limit|Pot Limit|Limit))(?: - Level (\S+))? \(([^/()]+)/([^/()]+?)(?: [A-Z]{3})?\)$`)
+var tablePattern = regexp.MustCompile(`^Table '(.+)' (\d+)-max(?: \(Play Money\))? Seat #(\d+) is the button$`)
+var seatPattern = regexp.MustCompile(`^Seat (\d+): (.+) \((\S+) in chips(?:, [^)]*)?\)( is sitting out| out of hand .*)?$`)
+var streetPattern = regexp.MustCompile(`^\*\*\* (FLOP|TURN|RIVER) \*\*\* \[([^\]]*)\](?: \[([^\]]*)\])?$`)
+var dealtPattern = regexp.MustCompile(`^Dealt to (.+?) \[([^\]]*)\]$`)
+var returnPattern = regexp.MustCompile(`^Uncalled bet \((\S+)\) returned to (.+)$`)
+var collectPattern = regexp.MustCompile(`^(.+) collected (\S+) from ((?:main |side )?pot(?:-\d+)?)$`)
+var showPattern = regexp.MustCompile(`^shows \[([^\]]*)\](?: \(.*\))?$`)
+var totalPattern = regexp.MustCompile(`^Total pot (\S+)(?: .*)? \| Rake (\S+)`)
+var boardPattern = regexp.MustCompile(`^Board \[([^\]]*)\]$`)
+
+// noisePattern is table chatter that is not part of the hand.
+var noisePattern = regexp.MustCompile(`^.+ (has timed out.*|is disconnected|is connected|has returned|leaves the table|joins the table at seat #\d+|will be allowed to play after the button|was removed from the table.*|finished the tournament.*|wins the tournament.*|is sitting out|said, ".*")$`)
+
+// actionPatterns read what follows "Name: ", less any " and is all-in".
+var actionPatterns = []struct {
+	pattern *regexp.Regexp
+	kind    string
+}{
+	{regexp.MustCompile(`^folds$`), holdem.Fold},
+	{regexp.MustCompile(`^checks$`), holdem.Check},
+	{regexp.MustCompile(`^calls (\S+)$`), holdem.Call},
+	{regexp.MustCompile(`^bets (\S+)$`), holdem.Bet},
+	{regexp.MustCompile(`^raises \S+ to (\S+)$`), holdem.Raise},
+	{regexp.MustCompile(`^posts small blind (\S+)$`), holdem.SmallBlind},
+	{regexp.MustCompile(`^posts big blind (\S+)$`), holdem.BigBlind},
+	{regexp.MustCompile(`^posts the ante (\S+)$`), holdem.Ante},
+	{regexp.MustCompile(`^posts small & big blinds (\S+)$`), BothBlinds},
+	{regexp.MustCompile(`^mucks hand$`), Muck},
+}
+
+// Sections of a PokerStars hand, in the order they come.
+const (
+	headerSection = iota
+	tableSection
+	seatsSection
+	bettingSection
+	summarySection
+)
+
+type pokerStarsParser struct {
+	hands   []Hand
+	hand    *Hand
+	section int
+	street  int
+}
+
+// ParsePokerStars reads every hand in a PokerStars hand history, where
+// hands are split by blank lines. Errors give the line they were found on.
+func ParsePokerStars(r io.Reader) ([]Hand, error) {
+	p := &pokerStarsParser{hands: []Hand{}}
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if err := p.line(text); err != nil {
+			return nil, fmt.Errorf("Invalid hand history: line %d %v", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := p.finish(); err != nil {
+		return nil, fmt.Errorf("Invalid hand history: line %d %v", line, err)
+	}
+	return p.hands, nil
+}
+
+func (p *pokerStarsParser) line(text string) error {
+	if text == "" {
+		return p.finish()
+	}
+	if p.hand == nil {
+		return p.header(text)
+	}
+
+	switch p.section {
+	case tableSection:
+		match := tablePattern.FindStringSubmatch(text)
+		if match == nil {
+			return fmt.Errorf("expected the table and button, not %q", text)
+		}
+		p.hand.Table = match[1]
+		p.hand.MaxSeats, _ = strconv.Atoi(match[2])
+		p.hand.Button, _ = strconv.Atoi(match[3])
+		p.section = seatsSection
+		return nil
+	case summarySection:
+		return p.summary(text)
+	}
+
+	if match := seatPattern.FindStringSubmatch(text); match != nil && p.section == seatsSection {
+		stack, err := p.amount(match[3])
+		if err != nil {
+			return err
+		}
+		number, _ := strconv.Atoi(match[1])
+		p.hand.Seats = append(p.hand.Seats, Seat{Number: number, Name: match[2], Stack: stack, SittingOut: match[4] != ""})
+		return nil
+	}
+
+	switch {
+	case text == "*** HOLE CARDS ***":
+		p.section, p.street = bettingSection, holdem.Preflop
+		return nil
+	case text == "*** SHOW DOWN ***":
+		p.street = holdem.Showdown
+		return nil
+	case text == "*** SUMMARY ***":
+		p.section = summarySection
+		return nil
+	case strings.HasPrefix(text, "*** "):
+		return p.deal(text)
+	}
+
+	if match := dealtPattern.FindStringSubmatch(text); match != nil {
+		seat := p.hand.Seat(match[1])
+		if seat == nil {
+			return fmt.Errorf("%q has no seat", match[1])
+		}
+		hole, err := cards(match[2])
+		if err != nil {
+			return err
+		}
+		seat.Hole = hole
+		if p.hand.Hero == "" {
+			p.hand.Hero = seat.Name
+		}
+		return nil
+	}
+	if match := returnPattern.FindStringSubmatch(text); match != nil {
+		amount, err := p.amount(match[1])
+		if err != nil {
+			return err
+		}
+		if p.hand.Seat(match[2]) == nil {
+			return fmt.Errorf("%q has no seat", match[2])
+		}
+		p.hand.Actions = append(p.hand.Actions, Action{Street: p.street, Player: match[2], Type: holdem.Return, Amount: amount})
+		return nil
+	}
+	if match := collectPattern.FindStringSubmatch(text); match != nil && p.hand.Seat(match[1]) != nil {
+		amount, err := p.amount(match[2])
+		if err != nil {
+			return err
+		}
+		p.hand.Wins = append(p.hand.Wins, Win{Player: match[1], Amount: amount, Pot: match[3]})
+		return nil
+	}
+
+	if name, rest, ok := p.player(text); ok {
+		return p.action(name, rest)
+	}
+	if noisePattern.MatchString(text) {
+		return nil
+	}
+	return fmt.Errorf("unexpected %q", text)
+}
+
+func (p *pokerStarsParser) header(text string) error {
+	match := headerPattern.FindStringSubmatch(text)
+	if match == nil {
+		return fmt.Errorf("expected a PokerStars hand to start, not %q", text)
+	}
+	game := gamePattern.FindStringSubmatch(match[2])
+	if game == nil {
+		return fmt.Errorf("unsupported game %q", match[2])
+	}
+
+	p.hand = &Hand{Site: PokerStars, ID: match[1], Tournament: game[1], BuyIn: strings.TrimSpace(game[2]), Game: game[3], Level: game[4], Date: match[3]}
+	if strings.ContainsAny(game[5][:1], "$€£") {
+		p.hand.Currency = game[5][:1]
+	}
+	var err error
+	if p.hand.SmallBlind, err = p.amount(game[5]); err != nil {
+		return err
+	}
+	if p.hand.BigBlind, err = p.amount(game[6]); err != nil {
+		return err
+	}
+	p.section, p.street = tableSection, holdem.Preflop
+	return nil
+}
+
+// deal reads the board dealt on the flop, turn or river.
+func (p *pokerStarsParser) deal(text string) error {
+	match := streetPattern.FindStringSubmatch(text)
+	if match == nil {
+		return fmt.Errorf("unexpected %q", text)
+	}
+	street := map[string]int{"FLOP": holdem.Flop, "TURN": holdem.Turn, "RIVER": holdem.River}[match[1]]
+	if p.section != bettingSection || street != p.street+1 {
+		return fmt.Errorf("%s out of order", strings.ToLower(match[1]))
+	}
+
+	dealt := match[3]
+	if street == holdem.Flop && match[3] == "" {
+		dealt = match[2]
+	} else if street == holdem.Flop {
+		return fmt.Errorf("board [%s] does not follow %v", match[2], p.hand.Board)
+	} else if before, err := cards(match[2]); err != nil || fmt.Sprint(before) != fmt.Sprint(p.hand.Board) {
+		return fmt.Errorf("board [%s] does not follow %v", match[2], p.hand.Board)
+	}
+	board, err := cards(dealt)
+	if err != nil {
+		return err
+	}
+	if expected := map[int]int{holdem.Flop: 3, holdem.Turn: 1, holdem.River: 1}[street]; len(board) != expected {
+		return fmt.Errorf("%s must deal %d cards, not %d", strings.ToLower(match[1]), expected, len(board))
+	}
+	p.hand.Board = append(p.hand.Board, board...)
+	p.street = street
+	return nil
+}
+
+// player finds whose line it is, "Name: ...", longest name first so that
+// one name starting another is not mistaken for it.
+func (p *pokerStarsParser) player(text string) (string, string, bool) {
+	names := []string{}
+	for _, seat := range p.hand.Seats {
+		names = append(names, seat.Name)
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		return len(names[i]) > len(names[j])
+	})
+	for _, name := range names {
+		if rest, ok := strings.CutPrefix(text, name+": "); ok {
+			return name, rest, true
+		}
+	}
+	return "", "", false
+}
+
+func (p *pokerStarsParser) action(name string, rest string) error {
+	if match := showPattern.FindStringSubmatch(rest); match != nil {
+		shown, err := cards(match[1])
+		if err != nil {
+			return err
+		}
+		p.hand.Seat(name).Hole = shown
+		p.hand.Actions = append(p.hand.Actions, Action{Street: p.street, Player: name, Type: Show, Cards: shown})
+		return nil
+	}
+	switch rest {
+	case "is sitting out", "sits out", "doesn't show hand", "is disconnected", "is connected":
+		return nil
+	}
+
+	rest, allIn := strings.CutSuffix(rest, " and is all-in")
+	for _, action := range actionPatterns {
+		match := action.pattern.FindStringSubmatch(rest)
+		if match == nil {
+			continue
+		}
+		amount := 0
+		if len(match) > 1 {
+			var err error
+			if amount, err = p.amount(match[1]); err != nil {
+				return err
+			}
+		}
+		if p.section != bettingSection && !isPost(action.kind) {
+			return fmt.Errorf("%s %s before the hole cards", name, rest)
+		}
+		if action.kind == holdem.Ante && p.hand.Ante == 0 {
+			p.hand.Ante = amount
+		}
+		p.hand.Actions = append(p.hand.Actions, Action{Street: p.street, Player: name, Type: action.kind, Amount: amount, AllIn: allIn})
+		return nil
+	}
+	return fmt.Errorf("unknown action %q by %s", rest, name)
+}
+
+func (p *pokerStarsParser) summary(text string) error {
+	if match := totalPattern.FindStringSubmatch(text); match != nil {
+		var err error
+		if p.hand.TotalPot, err = p.amount(match[1]); err != nil {
+			return err
+		}
+		p.hand.Rake, err = p.amount(match[2])
+		return err
+	}
+	if match := boardPattern.FindStringSubmatch(text); match != nil {
+		board, err := cards(match[1])
+		if err != nil {
+			return err
+		}
+		if fmt.Sprint(board) != fmt.Sprint(p.hand.Board) {
+			return fmt.Errorf("summary board %v is not the board dealt %v", board, p.hand.Board)
+		}
+	}
+	return nil
+}
+
+// finish ends the hand in progress, if any, at a blank line or the end.
+func (p *pokerStarsParser) finish() error {
+	if p.hand == nil {
+		return nil
+	}
+	if p.section != summarySection {
+		return fmt.Errorf("hand #%s ends before its summary", p.hand.ID)
+	}
+	if p.hand.Seat(p.buttonName()) == nil {
+		return fmt.Errorf("hand #%s has no player in button seat %d", p.hand.ID, p.hand.Button)
+	}
+	p.hands = append(p.hands, *p.hand)
+	p.hand = nil
+	return nil
+}
+
+func (p *pokerStarsParser) buttonName() string {
+	for _, seat := range p.hand.Seats {
+		if seat.Number == p.hand.Button {
+			return seat.Name
+		}
+	}
+	return ""
+}
+
+// amount reads chips, or money as cents when the hand has a currency.
+func (p *pokerStarsParser) amount(s string) (int, error) {
+	digits := strings.TrimLeft(s, "$€£")
+	if p.hand.Currency == "" {
+		chips, err := strconv.Atoi(digits)
+		if err != nil || chips < 0 {
+			return 0, fmt.Errorf("invalid amount %q", s)
+		}
+		return chips, nil
+	}
+
+	whole, fraction, _ := strings.Cut(digits, ".")
+	dollars, err := strconv.Atoi(whole)
+	if err != nil || dollars < 0 || len(fraction) > 2 {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	cents, err := strconv.Atoi((fraction + "00")[:2])
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	return 100*dollars + cents, nil
+}
+
+// cards reads cards written as in Ah Kd.
+func cards(s string) ([]string, error) {
+	return parser.ParseCards(strings.ToUpper(s))
+}
+
+// currencies are the codes PokerStars puts after cash game stakes.
+var currencies = map[string]string{"$": "USD", "€": "EUR", "£": "GBP"}
+
+// streetNames are the streets as the headers name them.
+var streetNames = map[int]string{holdem.Flop: "FLOP", holdem.Turn: "TURN", holdem.River: "RIVER"}
+
+// WritePokerStars writes the hands as a PokerStars hand history, split by
+// blank lines, that ParsePokerStars and tracking tools can read back.
+// Only the hero is dealt cards, as PokerStars and the trackers reading
+// its histories expect; everyone else's are seen when they show.
+func WritePokerStars(w io.Writer, hands []Hand) error {
+	for i, hand := range hands {
+		if !idPattern.MatchString(hand.ID) || hand.Date == "" || hand.Table == "" {
+			return fmt.Errorf("Invalid hand: PokerStars needs a numeric ID, a date and a table, not %q, %q and %q", hand.ID, hand.Date, hand.Table)
+		}
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := io.WriteString(w, pokerStarsText(hand)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func pokerStarsText(h Hand) string {
+	var b strings.Builder
+	stakes := h.money(h.SmallBlind) + "/" + h.money(h.BigBlind)
+	if h.Currency != "" {
+		stakes += " " + currencies[h.Currency]
+	}
+	if h.Tournament == "" {
+		fmt.Fprintf(&b, "PokerStars Hand #%s:  %s (%s) - %s\n", h.ID, h.Game, stakes, h.Date)
+	} else {
+		game := h.Game
+		if h.BuyIn != "" {
+			game = h.BuyIn + " " + game
+		}
+		if h.Level != "" {
+			game += " - Level " + h.Level
+		}
+		fmt.Fprintf(&b, "PokerStars Hand #%s: Tournament #%s, %s (%s) - %s\n", h.ID, h.Tournament, game, stakes, h.Date)
+	}
+	fmt.Fprintf(&b, "Table '%s' %d-max Seat #%d is the button\n", h.Table, h.MaxSeats, h.Button)
+	for _, seat := range h.Seats {
+		sittingOut := ""
+		if seat.SittingOut {
+			sittingOut = " is sitting out"
+		}
+		fmt.Fprintf(&b, "Seat %d: %s (%s in chips)%s\n", seat.Number, seat.Name, h.money(seat.Stack), sittingOut)
+	}
+
+	posts := 0
+	for posts < len(h.Actions) && isPost(h.Actions[posts].Type) {
+		fmt.Fprintf(&b, "%s\n", h.actionLine(posts))
+		posts++
+	}
+	b.WriteString("*** HOLE CARDS ***\n")
+	if hero := h.Seat(h.Hero); hero != nil && len(hero.Hole) > 0 {
+		fmt.Fprintf(&b, "Dealt to %s [%s]\n", hero.Name, written(hero.Hole))
+	}
+
+	street := holdem.Preflop
+	deal := func(to int) {
+		for ; street < min(to, holdem.River) && len(h.Board) >= 3+street; street++ {
+			dealt := h.Board[:3+street]
+			if street == holdem.Preflop {
+				fmt.Fprintf(&b, "*** FLOP *** [%s]\n", written(dealt))
+			} else {
+				fmt.Fprintf(&b, "*** %s *** [%s] [%s]\n", streetNames[street+1], written(dealt[:len(dealt)-1]), written(dealt[len(dealt)-1:]))
+			}
+		}
+		if to == holdem.Showdown && street < holdem.Showdown {
+			street = holdem.Showdown
+			b.WriteString("*** SHOW DOWN ***\n")
+		}
+	}
+	for i := posts; i < len(h.Actions); i++ {
+		deal(h.Actions[i].Street)
+		fmt.Fprintf(&b, "%s\n", h.actionLine(i))
+	}
+	deal(holdem.River)
+	for _, win := range h.Wins {
+		fmt.Fprintf(&b, "%s collected %s from %s\n", win.Player, h.money(win.Amount), win.Pot)
+	}
+
+	b.WriteString("*** SUMMARY ***\n")
+	fmt.Fprintf(&b, "Total pot %s | Rake %s\n", h.money(h.TotalPot), h.money(h.Rake))
+	if len(h.Board) > 0 {
+		fmt.Fprintf(&b, "Board [%s]\n", written(h.Board))
+	}
+	for _, seat := range h.Seats {
+		if !seat.SittingOut {
+			fmt.Fprintf(&b, "Seat %d: %s%s %s\n", seat.Number, seat.Name, h.position(seat), h.outcome(seat.Name))
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func isPost(kind string) bool {
+	return kind == holdem.Ante || kind == holdem.SmallBlind || kind == holdem.BigBlind || kind == BothBlinds
+}
+
+// actionLine writes action i as PokerStars does.
+func (h Hand) actionLine(i int) string {
+	action := h.Actions[i]
+	var line string
+	switch action.Type {
+	case holdem.Fold:
+		line = "folds"
+	case holdem.Check:
+		line = "checks"
+	case holdem.Call:
+		line = "calls " + h.money(action.Amount)
+	case holdem.Bet:
+		line = "bets " + h.money(action.Amount)
+	case holdem.Raise:
+		current := 0
+		for _, seat := range h.Seats {
+			current = max(current, h.streetBet(h.Actions[:i], seat.Name, action.Street))
+		}
+		line = "raises " + h.money(action.Amount-current) + " to " + h.money(action.Amount)
+	case holdem.SmallBlind:
+		line = "posts small blind " + h.money(action.Amount)
+	case holdem.BigBlind:
+		line = "posts big blind " + h.money(action.Amount)
+	case holdem.Ante:
+		line = "posts the ante " + h.money(action.Amount)
+	case BothBlinds:
+		line = "posts small & big blinds " + h.money(action.Amount)
+	case Show:
+		line = "shows [" + written(action.Cards) + "]"
+	case Muck:
+		line = "mucks hand"
+	case holdem.Return:
+		return fmt.Sprintf("Uncalled bet (%s) returned to %s", h.money(action.Amount), action.Player)
+	}
+	if action.AllIn {
+		line += " and is all-in"
+	}
+	return action.Player + ": " + line
+}
+
+// position is the button or blind the seat was, for the summary.
+func (h Hand) position(seat Seat) string {
+	position := ""
+	if seat.Number == h.Button {
+		position = " (button)"
+	}
+	for _, action := range h.Actions {
+		if action.Player == seat.Name && action.Type == holdem.SmallBlind {
+			position += " (small blind)"
+		} else if action.Player == seat.Name && (action.Type == holdem.BigBlind || action.Type == BothBlinds) {
+			position += " (big blind)"
+		}
+	}
+	return position
+}
+
+// outcome is how the hand went for the player, for the summary.
+func (h Hand) outcome(name string) string {
+	won := 0
+	for _, win := range h.Wins {
+		if win.Player == name {
+			won += win.Amount
+		}
+	}
+	for _, action := range h.Actions {
+		if action.Player != name {
+			continue
+		}
+		switch {
+		case action.Type == holdem.Fold && action.Street == holdem.Preflop:
+			return "folded before Flop"
+		case action.Type == holdem.Fold:
+			name := streetNames[action.Street]
+			return "folded on the " + name[:1] + strings.ToLower(name[1:])
+		case action.Type == Show && won > 0:
+			return fmt.Sprintf("showed [%s] and won (%s)", written(action.Cards), h.money(won))
+		case action.Type == Show:
+			return fmt.Sprintf("showed [%s] and lost", written(action.Cards))
+		case action.Type == Muck:
+			return "mucked"
+		}
+	}
+	if won > 0 {
+		return fmt.Sprintf("collected (%s)", h.money(won))
+	}
+	return "mucked"
+}
+
+// money writes chips, or cents as money when the hand has a currency.
+func (h Hand) money(amount int) string {
+	if h.Currency == "" {
+		return strconv.Itoa(amount)
+	}
+	if amount%100 == 0 {
+		return fmt.Sprintf("%s%d", h.Currency, amount/100)
+	}
+	return fmt.Sprintf("%s%d.%02d", h.Currency, amount/100, amount%100)
+}
+
+// written writes cards as in Ah Kd.
+func written(cards []string) string {
+	return strings.Join(lower(cards), " ")
+}
+
+// lower writes each card with its suit in lower case, as in Ah.
+func lower(cards []string) []string {
+	if cards == nil {
+		return nil
+	}
+	lowered := []string{}
+	for _, card := range cards {
+		lowered = append(lowered, card[:1]+strings.ToLower(card[1:]))
+	}
+	return lowered
+}
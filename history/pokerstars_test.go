@@ -0,0 +1,180 @@
+package history // github.com/sildani/poker-hands-go/history
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const cashHand = `PokerStars Hand #210987654321:  Hold'em No Limit ($0.01/$0.02 USD) - 2020/03/14 21:07:15 ET
+Table 'Alcyone II' 6-max Seat #3 is the button
+Seat 1: Villain One ($2.13 in chips)
+Seat 2: Sitter ($1 in chips) is sitting out
+Seat 3: Hero ($2 in chips)
+Seat 5: Villain ($1.50 in chips)
+Villain: posts small blind $0.01
+Villain One: posts big blind $0.02
+*** HOLE CARDS ***
+Dealt to Hero [Ah Kd]
+Hero: raises $0.04 to $0.06
+Villain: calls $0.05
+Villain One: folds
+Villain One said, "nh"
+*** FLOP *** [2c 3d Kh]
+Villain: checks
+Hero: bets $0.10
+Villain: raises $0.20 to $0.30
+Hero: raises $1.64 to $1.94 and is all-in
+Villain: calls $1.14 and is all-in
+Uncalled bet ($0.50) returned to Hero
+*** TURN *** [2c 3d Kh] [5s]
+*** RIVER *** [2c 3d Kh 5s] [9c]
+*** SHOW DOWN ***
+Villain: shows [Qs Qd] (a pair of Queens)
+Hero: shows [Ah Kd] (a pair of Kings)
+Hero collected $2.91 from pot
+*** SUMMARY ***
+Total pot $3.02 | Rake $0.11
+Board [2c 3d Kh 5s 9c]
+Seat 1: Villain One (big blind) folded before Flop
+Seat 3: Hero (button) showed [Ah Kd] and won ($2.91) with a pair of Kings
+Seat 5: Villain (small blind) showed [Qs Qd] and lost with a pair of Queens`
+
+const tournamentHand = `PokerStars Hand #2: Tournament #3001, $1+$0.10 USD Hold'em No Limit - Level II (15/30) - 2020/03/14 21:10:00 ET
+Table '3001 1' 9-max Seat #1 is the button
+Seat 1: A (1500 in chips)
+Seat 2: B (1500 in chips)
+A: posts the ante 5
+B: posts the ante 5
+A: posts small blind 15
+B: posts big blind 30
+*** HOLE CARDS ***
+A: folds
+Uncalled bet (15) returned to B
+B collected 40 from pot
+B: doesn't show hand
+*** SUMMARY ***
+Total pot 40 | Rake 0
+Seat 1: A (button) (small blind) folded before Flop
+Seat 2: B (big blind) collected (40)`
+
+func TestParsePokerStars(t *testing.T) {
+	hands, err := ParsePokerStars(strings.NewReader("\ufeff" + cashHand + "\n\n\n" + tournamentHand + "\n"))
+	if err != nil {
+		t.Fatalf("ParsePokerStars err == %q but expected nil", err)
+	}
+	if len(hands) != 2 {
+		t.Fatalf("ParsePokerStars read %d hands but expected 2", len(hands))
+	}
+
+	cash := hands[0]
+	for _, test := range []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{"header", []interface{}{cash.Site, cash.ID, cash.Game, cash.Currency, cash.SmallBlind, cash.BigBlind, cash.Date}, "[PokerStars 210987654321 Hold'em No Limit $ 1 2 2020/03/14 21:07:15 ET]"},
+		{"table", []interface{}{cash.Table, cash.MaxSeats, cash.Button}, "[Alcyone II 6 3]"},
+		{"seats", cash.Seats, "[{1 Villain One 213 false []} {2 Sitter 100 true []} {3 Hero 200 false [AH KD]} {5 Villain 150 false [QS QD]}]"},
+		{"board", cash.Board, "[2C 3D KH 5S 9C]"},
+		{"preflop", cash.Actions[:5], "[{0 Villain small blind 1 false []} {0 Villain One big blind 2 false []} {0 Hero raise 6 false []} {0 Villain call 5 false []} {0 Villain One fold 0 false []}]"},
+		{"flop", cash.Actions[5:10], "[{1 Villain check 0 false []} {1 Hero bet 10 false []} {1 Villain raise 30 false []} {1 Hero raise 194 true []} {1 Villain call 114 true []}]"},
+		{"showdown", cash.Actions[10:], "[{1 Hero return 50 false []} {4 Villain show 0 false [QS QD]} {4 Hero show 0 false [AH KD]}]"},
+		{"wins", cash.Wins, "[{Hero 291 pot}]"},
+		{"pot", []int{cash.TotalPot, cash.Rake}, "[302 11]"},
+	} {
+		if fmt.Sprint(test.value) != test.expected {
+			t.Errorf("%s == %v but expected %s", test.name, test.value, test.expected)
+		}
+	}
+
+	tournament := hands[1]
+	if tournament.Tournament != "3001" || tournament.Currency != "" || tournament.BigBlind != 30 || tournament.Ante != 5 || len(tournament.Actions) != 6 {
+		t.Errorf("tournament hand == %+v but expected tournament 3001 at 15/30 with a 5 ante", tournament)
+	}
+	if fmt.Sprint(tournament.Actions[0]) != "{0 A ante 5 false []}" {
+		t.Errorf("tournament.Actions[0] == %v but expected A's ante preflop", tournament.Actions[0])
+	}
+	if fmt.Sprint(tournament.Wins) != "[{B 40 pot}]" || tournament.TotalPot != 40 {
+		t.Errorf("tournament wins == %v from %d but expected B to collect 40", tournament.Wins, tournament.TotalPot)
+	}
+}
+
+func TestParsePokerStarsErrors(t *testing.T) {
+	lines := strings.Split(cashHand, "\n")
+	edit := func(line int, text string) string {
+		edited := append([]string{}, lines...)
+		edited[line-1] = text
+		return strings.Join(edited, "\n")
+	}
+
+	for _, test := range []struct {
+		input       string
+		expectedErr string
+	}{
+		{"Full Tilt Poker Game #1", `Invalid hand history: line 1 expected a PokerStars hand to start, not "Full Tilt Poker Game #1"`},
+		{edit(1, "PokerStars Hand #1: Razz ($0.01/$0.02 USD) - 2020/03/14 21:07:15 ET"), `Invalid hand history: line 1 unsupported game "Razz ($0.01/$0.02 USD)"`},
+		{edit(2, "Seat 1: Villain One ($2.13 in chips)"), `Invalid hand history: line 2 expected the table and button, not "Seat 1: Villain One ($2.13 in chips)"`},
+		{edit(3, "Seat 1: Villain One ($2.134 in chips)"), `Invalid hand history: line 3 invalid amount "$2.134"`},
+		{edit(10, "Dealt to Hero [Ah Kx]"), "Invalid hand history: line 10 Invalid cards: contains invalid card"},
+		{edit(10, "Dealt to Nobody [Ah Kd]"), `Invalid hand history: line 10 "Nobody" has no seat`},
+		{edit(11, "Hero: dances"), `Invalid hand history: line 11 unknown action "dances" by Hero`},
+		{edit(12, "Someone: calls $0.05"), `Invalid hand history: line 12 unexpected "Someone: calls $0.05"`},
+		{edit(15, "*** TURN *** [2c 3d Kh] [5s]"), "Invalid hand history: line 15 turn out of order"},
+		{edit(15, "*** FLOP *** [2c 3d]"), "Invalid hand history: line 15 flop must deal 3 cards, not 2"},
+		{edit(22, "*** TURN *** [2c 3d Ks] [5s]"), "Invalid hand history: line 22 board [2c 3d Ks] does not follow [2C 3D KH]"},
+		{edit(31, "Board [2c 3d Kh 5s 9d]"), "Invalid hand history: line 31 summary board [2C 3D KH 5S 9D] is not the board dealt [2C 3D KH 5S 9C]"},
+		{strings.Join(lines[:27], "\n"), "Invalid hand history: line 27 hand #210987654321 ends before its summary"},
+		{edit(2, "Table 'Alcyone II' 6-max Seat #4 is the button"), "Invalid hand history: line 33 hand #210987654321 has no player in button seat 4"},
+	} {
+		if _, err := ParsePokerStars(strings.NewReader(test.input)); err == nil || err.Error() != test.expectedErr {
+			t.Errorf("ParsePokerStars err == %v but expected %q", err, test.expectedErr)
+		}
+	}
+}
+
+func TestWritePokerStars(t *testing.T) {
+	hands, _ := ParsePokerStars(strings.NewReader(cashHand + "\n\n" + tournamentHand))
+	var written strings.Builder
+	if err := WritePokerStars(&written, hands); err != nil {
+		t.Fatalf("WritePokerStars err == %q but expected nil", err)
+	}
+
+	reread, err := ParsePokerStars(strings.NewReader(written.String()))
+	if err != nil || fmt.Sprint(reread) != fmt.Sprint(hands) {
+		t.Errorf("ParsePokerStars(WritePokerStars) == %v, %v but expected %v", reread, err, hands)
+	}
+
+	if hands[0].Hero != "Hero" || strings.Count(written.String(), "Dealt to") != 1 {
+		t.Errorf("Hero == %q dealt %d times but expected only Hero dealt cards", hands[0].Hero, strings.Count(written.String(), "Dealt to"))
+	}
+
+	lines := strings.Split(written.String(), "\n")
+	for _, test := range []struct {
+		line     int
+		expected string
+	}{
+		{1, "PokerStars Hand #210987654321:  Hold'em No Limit ($0.01/$0.02 USD) - 2020/03/14 21:07:15 ET"},
+		{4, "Seat 2: Sitter ($1 in chips) is sitting out"},
+		{10, "Dealt to Hero [Ah Kd]"},
+		{11, "Hero: raises $0.04 to $0.06"},
+		{17, "Villain: raises $0.20 to $0.30"},
+		{18, "Hero: raises $1.64 to $1.94 and is all-in"},
+		{22, "*** RIVER *** [2c 3d Kh 5s] [9c]"},
+		{24, "Villain: shows [Qs Qd]"},
+		{30, "Seat 1: Villain One (big blind) folded before Flop"},
+		{31, "Seat 3: Hero (button) showed [Ah Kd] and won ($2.91)"},
+		{32, "Seat 5: Villain (small blind) showed [Qs Qd] and lost"},
+		{34, "PokerStars Hand #2: Tournament #3001, $1+$0.10 USD Hold'em No Limit - Level II (15/30) - 2020/03/14 21:10:00 ET"},
+		{49, "Seat 2: B (big blind) collected (40)"},
+	} {
+		if lines[test.line-1] != test.expected {
+			t.Errorf("line %d == %q but expected %q", test.line, lines[test.line-1], test.expected)
+		}
+	}
+
+	hands[0].ID = "unknown"
+	if err := WritePokerStars(&written, hands); err == nil {
+		t.Errorf("WritePokerStars(ID unknown) err == nil but expected a numeric ID")
+	}
+}
@@ -0,0 +1,377 @@
+package holdem // github.com/sildani/poker-hands-go/holdem
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sildani/poker-hands-go/betting"
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/evaluator"
+	"github.com/sildani/poker-hands-go/pot"
+)
+
+const (
+	Preflop = iota
+	Flop
+	Turn
+	River
+	Showdown
+)
+
+const Fold = betting.Fold
+const Check = betting.Check
+const Call = betting.Call
+const Bet = betting.Bet
+const Raise = betting.Raise
+
+// Variants dealt the same way: Omaha deals four hole cards and a hand uses
+// exactly two of them with three from the board. Hi-lo splits each pot
+// with the best eight or better low.
+const Holdem = "hold'em"
+const Omaha = "omaha"
+const OmahaHiLo = "omaha hi-lo"
+
+// Events that are not player decisions.
+const Ante = "ante"
+const SmallBlind = "small blind"
+const BigBlind = "big blind"
+const Return = betting.Return
+const Win = betting.Win
+
+// Config sets the variant, hold'em unless set, and the stakes. Limit is the betting package structure,
+// no-limit unless set; in fixed-limit the bets are the big blind before the
+// turn and twice that after. Cap limits the bets and raises on a street,
+// the big blind counting as the first, and zero is no cap. OddChip is the
+// pot package rule for odd chips in a split pot, left of the button unless
+// set.
+type Config struct {
+	Variant    string
+	SmallBlind int
+	BigBlind   int
+	Ante       int
+	Limit      string
+	Cap        int
+	OddChip    string
+}
+
+type Seat struct {
+	Name  string
+	Stack int
+}
+
+type Player struct {
+	Name string
+	Hole []string
+	betting.Chips
+}
+
+// Action is a decision by the player to act. For a bet or raise Amount is
+// the total the player's bet comes to on this street.
+type Action struct {
+	Type   string
+	Amount int
+}
+
+// Event is something that happened in the hand. For a call Amount is what
+// was put in; for a bet or raise it is the total bet on this street.
+type Event = betting.Event
+
+// View is the hand as one seat sees it: everyone's chips and actions but
+// only its own hole cards, which are also in Hole, and once the hand is
+// over the hole cards shown down.
+type View struct {
+	Seat       int
+	Hole       []string
+	Board      []string
+	Street     int
+	Button     int
+	Players    []Player
+	Pot        int
+	CurrentBet int
+	BigBlind   int
+	Options    Options
+	Events     []Event
+}
+
+// Options are the legal actions for the player to act, worked out by the
+// betting package.
+type Options = betting.Options
+
+// Hand is a Hold'em or Omaha hand as a state machine: NewHand posts the
+// antes and blinds and deals, then each Act moves the hand on, dealing the
+// board and settling the pot once the betting is done.
+type Hand struct {
+	config  Config
+	players []*Player
+	button  int
+	deck    *deck.Deck
+	board   []string
+	table   betting.Table
+	pots    []pot.Pot
+}
+
+func NewHand(config Config, seats []Seat, button int, d *deck.Deck) (*Hand, error) {
+	if len(seats) < 2 || len(seats) > 10 {
+		return nil, errors.New("Invalid table: must have two to ten players")
+	}
+	if config.BigBlind <= 0 || config.SmallBlind < 0 || config.SmallBlind > config.BigBlind || config.Ante < 0 {
+		return nil, errors.New("Invalid blinds: big blind must be positive and at least the small blind")
+	}
+	if button < 0 || button >= len(seats) {
+		return nil, fmt.Errorf("Invalid button: must be a seat from 0 to %d", len(seats)-1)
+	}
+	if config.Limit != "" && config.Limit != betting.NoLimit && config.Limit != betting.PotLimit &&
+		config.Limit != betting.FixedLimit {
+		return nil, fmt.Errorf("Invalid limit: unknown betting structure %q", config.Limit)
+	}
+	if config.Variant != "" && config.Variant != Holdem && config.Variant != Omaha && config.Variant != OmahaHiLo {
+		return nil, fmt.Errorf("Invalid variant: unknown game %q", config.Variant)
+	}
+
+	h := &Hand{config: config, button: button, deck: d, board: []string{}}
+	chips := []*betting.Chips{}
+	for _, seat := range seats {
+		if seat.Stack <= 0 {
+			return nil, fmt.Errorf("Invalid seat: %s has no chips", seat.Name)
+		}
+		p := &Player{Name: seat.Name, Hole: []string{}, Chips: betting.Chips{Stack: seat.Stack}}
+		h.players = append(h.players, p)
+		chips = append(chips, &p.Chips)
+	}
+	h.table = betting.NewTable(chips)
+
+	if config.Ante > 0 {
+		for i := range h.players {
+			h.table.Post(i, Ante, config.Ante)
+		}
+		for _, p := range h.players {
+			p.Bet = 0
+		}
+	}
+
+	smallBlind, bigBlind := h.next(button), h.next(h.next(button))
+	if len(seats) == 2 {
+		smallBlind, bigBlind = button, h.next(button)
+	}
+	h.table.Post(smallBlind, SmallBlind, config.SmallBlind)
+	h.table.Post(bigBlind, BigBlind, config.BigBlind)
+	h.table.Round = betting.NewRound(config.Limit, config.BigBlind, config.Cap)
+	h.table.Round.CurrentBet = max(h.players[smallBlind].Bet, h.players[bigBlind].Bet)
+	h.table.Round.FullBet = config.BigBlind
+	h.table.Round.Raises = 1
+
+	holeCards := 2
+	if config.Variant == Omaha || config.Variant == OmahaHiLo {
+		holeCards = 4
+	}
+	for round := 0; round < holeCards; round++ {
+		for i := range h.players {
+			card, err := d.Deal(1)
+			if err != nil {
+				return nil, err
+			}
+			seat := (smallBlind + i) % len(h.players)
+			h.players[seat].Hole = append(h.players[seat].Hole, card[0])
+		}
+	}
+
+	h.table.ToAct = bigBlind
+	h.advance()
+	return h, nil
+}
+
+func (h *Hand) Players() []Player {
+	players := []Player{}
+	for _, p := range h.players {
+		player := *p
+		player.Hole = append([]string{}, p.Hole...)
+		players = append(players, player)
+	}
+	return players
+}
+
+func (h *Hand) Button() int {
+	return h.button
+}
+
+func (h *Hand) Board() []string {
+	return append([]string{}, h.board...)
+}
+
+func (h *Hand) Street() int {
+	return h.table.Street
+}
+
+// ToAct is the seat to act, or -1 once the hand is over.
+func (h *Hand) ToAct() int {
+	if h.Done() {
+		return -1
+	}
+	return h.table.ToAct
+}
+
+func (h *Hand) Done() bool {
+	return h.table.Street == Showdown
+}
+
+func (h *Hand) CurrentBet() int {
+	return h.table.Round.CurrentBet
+}
+
+func (h *Hand) Pot() int {
+	return h.table.Pot()
+}
+
+func (h *Hand) Events() []Event {
+	return append([]Event{}, h.table.Events...)
+}
+
+// Pots are the main pot and any side pots once the hand is over. Win
+// events come in the same order.
+func (h *Hand) Pots() []pot.Pot {
+	return append([]pot.Pot{}, h.pots...)
+}
+
+// Winnings is what each seat took from the pot once the hand is over.
+func (h *Hand) Winnings() []int {
+	return append([]int{}, h.table.Winnings...)
+}
+
+// View is what seat may see of the hand, with its options when it is to
+// act.
+func (h *Hand) View(seat int) View {
+	players := h.Players()
+	showdown := h.Done() && h.table.Contenders() > 1
+	for i := range players {
+		if i != seat && !(showdown && !players[i].Folded) {
+			players[i].Hole = []string{}
+		}
+	}
+	view := View{
+		Seat:       seat,
+		Hole:       append([]string{}, h.players[seat].Hole...),
+		Board:      h.Board(),
+		Street:     h.table.Street,
+		Button:     h.button,
+		Players:    players,
+		Pot:        h.Pot(),
+		CurrentBet: h.table.Round.CurrentBet,
+		BigBlind:   h.config.BigBlind,
+		Events:     h.Events(),
+	}
+	if h.ToAct() == seat {
+		view.Options = h.Options()
+	}
+	return view
+}
+
+func (h *Hand) Options() Options {
+	if h.Done() {
+		return Options{}
+	}
+	return h.table.Options()
+}
+
+func (h *Hand) Act(action Action) error {
+	if h.Done() {
+		return errors.New("Invalid action: the hand is over")
+	}
+	p := h.players[h.table.ToAct]
+	if err := h.table.Act(p.Name, action.Type, action.Amount, h.Options()); err != nil {
+		return err
+	}
+	h.advance()
+	return nil
+}
+
+// advance moves on to the next player to act, dealing the next street or
+// settling the hand when the betting is done.
+func (h *Hand) advance() {
+	for !h.Done() {
+		if h.table.Contenders() == 1 {
+			h.table.ReturnUncalled()
+			h.settle()
+			return
+		}
+		if h.table.Next() {
+			return
+		}
+
+		h.table.ReturnUncalled()
+		if h.table.Street == River {
+			h.settle()
+			return
+		}
+		h.nextStreet()
+	}
+}
+
+func (h *Hand) nextStreet() {
+	betSize := h.config.BigBlind
+	if h.config.Limit == betting.FixedLimit && h.table.Street+1 >= Turn {
+		betSize *= 2
+	}
+	h.table.NextStreet(betting.NewRound(h.config.Limit, betSize, h.config.Cap))
+
+	cards := 1
+	if h.table.Street == Flop {
+		cards = 3
+	}
+	h.deck.Burn()
+	dealt, _ := h.deck.Deal(cards)
+	h.board = append(h.board, dealt...)
+
+	// Action starts left of the button; advance looks from the seat after
+	// ToAct.
+	h.table.ToAct = h.button
+}
+
+// settle builds the main and side pots and awards each to the best hand
+// eligible for it, or in hi-lo splits it with the best low.
+func (h *Hand) settle() {
+	holes := [][]string{}
+	scores := make([]int, len(h.players))
+	lows := make([]int, len(h.players))
+	for i, p := range h.players {
+		holes = append(holes, p.Hole)
+		if !p.Folded && h.table.Contenders() > 1 {
+			scores[i], lows[i] = h.score(p.Hole)
+		}
+	}
+
+	h.pots = h.table.Settle(Showdown, func(p pot.Pot) []int {
+		if h.config.Variant == OmahaHiLo {
+			return pot.AwardSplit([]pot.Pot{p}, scores, lows, holes, h.button, h.config.OddChip)
+		}
+		return pot.Award([]pot.Pot{p}, scores, holes, h.button, h.config.OddChip)
+	})
+}
+
+// score ranks the best hand from the hole cards and board, and in Omaha
+// hi-lo the best eight or better low, 0 for none.
+func (h *Hand) score(hole []string) (int, int) {
+	if len(hole) == 2 {
+		return evaluator.Score(append(append([]string{}, h.board...), hole...)), 0
+	}
+
+	high, low := 0, 0
+	for i := 0; i < len(hole); i++ {
+		for j := i + 1; j < len(hole); j++ {
+			for a := 0; a < len(h.board); a++ {
+				for b := a + 1; b < len(h.board); b++ {
+					for c := b + 1; c < len(h.board); c++ {
+						cards := []string{hole[i], hole[j], h.board[a], h.board[b], h.board[c]}
+						high = max(high, evaluator.Score(cards))
+						if h.config.Variant == OmahaHiLo {
+							low = max(low, evaluator.EightOrBetter(cards))
+						}
+					}
+				}
+			}
+		}
+	}
+	return high, low
+}
+
+func (h *Hand) next(seat int) int {
+	return (seat + 1) % len(h.players)
+}
@@ -0,0 +1,406 @@
+package holdem // github.com/sildani/poker-hands-go/holdem
+
+import (
+	"fmt"
+	"github.com/sildani/poker-hands-go/betting"
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/pot"
+	"testing"
+)
+
+// stacked deals holes (by seat) one card at a time starting with the small
+// blind, then burns 2D, 3D and 4D before the flop, turn and river.
+func stacked(t *testing.T, smallBlind int, holes [][]string, board []string) *deck.Deck {
+	cards := []string{}
+	for round := 0; round < len(holes[0]); round++ {
+		for i := range holes {
+			cards = append(cards, holes[(smallBlind+i)%len(holes)][round])
+		}
+	}
+	cards = append(cards, "2D", board[0], board[1], board[2], "3D", board[3], "4D", board[4])
+	d, err := deck.NewStacked(cards)
+	if err != nil {
+		t.Fatalf("deck.NewStacked(%v) err == %q", cards, err)
+	}
+	return d
+}
+
+func seats(stacks ...int) []Seat {
+	seats := []Seat{}
+	for i, stack := range stacks {
+		seats = append(seats, Seat{Name: fmt.Sprintf("Player %d", i+1), Stack: stack})
+	}
+	return seats
+}
+
+func act(t *testing.T, h *Hand, actions ...Action) {
+	for _, action := range actions {
+		seat := h.ToAct()
+		if err := h.Act(action); err != nil {
+			t.Fatalf("seat %d Act(%v) err == %q but expected nil", seat, action, err)
+		}
+	}
+}
+
+func TestNewHandInvalid(t *testing.T) {
+	tests := []struct {
+		config      Config
+		seats       []Seat
+		button      int
+		expectedErr string
+	}{
+		{Config{SmallBlind: 1, BigBlind: 2}, seats(100), 0, "Invalid table: must have two to ten players"},
+		{Config{SmallBlind: 1, BigBlind: 2}, seats(1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1), 0, "Invalid table: must have two to ten players"},
+		{Config{SmallBlind: 2, BigBlind: 1}, seats(100, 100), 0, "Invalid blinds: big blind must be positive and at least the small blind"},
+		{Config{SmallBlind: 0, BigBlind: 0}, seats(100, 100), 0, "Invalid blinds: big blind must be positive and at least the small blind"},
+		{Config{SmallBlind: 1, BigBlind: 2}, seats(100, 100), 2, "Invalid button: must be a seat from 0 to 1"},
+		{Config{SmallBlind: 1, BigBlind: 2}, seats(100, 0), 0, "Invalid seat: Player 2 has no chips"},
+		{Config{SmallBlind: 1, BigBlind: 2, Variant: "pineapple"}, seats(100, 100), 0, "Invalid variant: unknown game \"pineapple\""},
+		{Config{SmallBlind: 1, BigBlind: 2, Limit: "spread-limit"}, seats(100, 100), 0, "Invalid limit: unknown betting structure \"spread-limit\""},
+	}
+
+	for _, test := range tests {
+		_, err := NewHand(test.config, test.seats, test.button, ordered())
+		if err == nil || err.Error() != test.expectedErr {
+			t.Errorf("NewHand(%v, %v, %d) err == %v but expected %q", test.config, test.seats, test.button, err, test.expectedErr)
+		}
+	}
+}
+
+func TestNewHandPostsAndDeals(t *testing.T) {
+	h, err := NewHand(Config{SmallBlind: 1, BigBlind: 2, Ante: 1}, seats(100, 100, 100), 0, ordered())
+	if err != nil {
+		t.Fatalf("NewHand err == %q but expected nil", err)
+	}
+
+	players := h.Players()
+	expectedStacks := []int{99, 98, 97}
+	expectedHoles := []string{"[4H 7H]", "[2H 5H]", "[3H 6H]"}
+	for i, p := range players {
+		if p.Stack != expectedStacks[i] {
+			t.Errorf("seat %d stack == %d but expected %d", i, p.Stack, expectedStacks[i])
+		}
+		if fmt.Sprint(p.Hole) != expectedHoles[i] {
+			t.Errorf("seat %d hole == %v but expected %s", i, p.Hole, expectedHoles[i])
+		}
+	}
+	if h.Pot() != 6 || h.CurrentBet() != 2 || h.ToAct() != 0 || h.Street() != Preflop {
+		t.Errorf("pot %d, bet %d, seat %d to act on street %d but expected pot 6, bet 2, seat 0 preflop",
+			h.Pot(), h.CurrentBet(), h.ToAct(), h.Street())
+	}
+
+	options := h.Options()
+	if options != (Options{Check: false, Call: 2, MinRaise: 4, MaxRaise: 99}) {
+		t.Errorf("Options() == %+v but expected call 2 or raise from 4 to 99", options)
+	}
+}
+
+func TestHeadsUpOrder(t *testing.T) {
+	h, _ := NewHand(Config{SmallBlind: 1, BigBlind: 2}, seats(100, 100), 1, ordered())
+
+	// The button posts the small blind and acts first before the flop only.
+	if h.Players()[1].Bet != 1 || h.ToAct() != 1 {
+		t.Errorf("button bet %d and seat %d to act but expected the button to post 1 and act", h.Players()[1].Bet, h.ToAct())
+	}
+	act(t, h, Action{Type: Call})
+	if h.ToAct() != 0 || !h.Options().Check || h.Options().MinRaise != 4 {
+		t.Errorf("seat %d to act with %+v but expected the big blind to have the option", h.ToAct(), h.Options())
+	}
+	act(t, h, Action{Type: Check})
+	if h.Street() != Flop || len(h.Board()) != 3 || h.ToAct() != 0 {
+		t.Errorf("street %d, board %v and seat %d to act but expected the flop with the big blind first",
+			h.Street(), h.Board(), h.ToAct())
+	}
+}
+
+func TestEveryoneFolds(t *testing.T) {
+	h, _ := NewHand(Config{SmallBlind: 1, BigBlind: 2}, seats(100, 100, 100), 0, ordered())
+	act(t, h, Action{Type: Fold}, Action{Type: Fold})
+
+	if !h.Done() || h.ToAct() != -1 {
+		t.Errorf("Done() == %t with seat %d to act but expected the hand to be over", h.Done(), h.ToAct())
+	}
+	// The big blind gets back the uncalled 1 and wins the 2 that was called.
+	if fmt.Sprint(h.Winnings()) != "[0 0 2]" {
+		t.Errorf("Winnings() == %v but expected the big blind to win 2", h.Winnings())
+	}
+	stacks := []int{}
+	for _, p := range h.Players() {
+		stacks = append(stacks, p.Stack)
+	}
+	if fmt.Sprint(stacks) != "[100 99 101]" {
+		t.Errorf("stacks == %v but expected [100 99 101]", stacks)
+	}
+	if err := h.Act(Action{Type: Check}); err == nil || err.Error() != "Invalid action: the hand is over" {
+		t.Errorf("Act after the hand err == %v but expected the hand to be over", err)
+	}
+}
+
+func TestShowdown(t *testing.T) {
+	holes := [][]string{{"AS", "AC"}, {"KS", "KC"}, {"7C", "2S"}}
+	board := []string{"AH", "KH", "9D", "5C", "3S"}
+	h, _ := NewHand(Config{SmallBlind: 5, BigBlind: 10}, seats(1000, 1000, 1000), 0, stacked(t, 1, holes, board))
+
+	act(t, h,
+		Action{Type: Raise, Amount: 30}, Action{Type: Call}, Action{Type: Fold},
+		Action{Type: Check}, Action{Type: Bet, Amount: 50}, Action{Type: Call},
+		Action{Type: Check}, Action{Type: Check},
+		Action{Type: Bet, Amount: 100}, Action{Type: Raise, Amount: 300}, Action{Type: Call},
+	)
+
+	if !h.Done() {
+		t.Fatalf("Done() == false but expected a showdown")
+	}
+	if fmt.Sprint(h.Board()) != fmt.Sprint(board) {
+		t.Errorf("Board() == %v but expected %v", h.Board(), board)
+	}
+	if fmt.Sprint(h.Winnings()) != "[770 0 0]" {
+		t.Errorf("Winnings() == %v but expected the aces to win 770", h.Winnings())
+	}
+	if h.Players()[0].Stack != 1390 || h.Players()[1].Stack != 620 || h.Players()[2].Stack != 990 {
+		t.Errorf("stacks after showdown are %d, %d and %d but expected 1390, 620 and 990",
+			h.Players()[0].Stack, h.Players()[1].Stack, h.Players()[2].Stack)
+	}
+}
+
+func TestSplitPot(t *testing.T) {
+	holes := [][]string{{"AS", "2C"}, {"AC", "3C"}, {"7C", "8S"}}
+	board := []string{"KH", "QH", "JD", "TC", "5S"}
+	h, _ := NewHand(Config{SmallBlind: 5, BigBlind: 10, Ante: 1}, seats(1000, 1000, 1000), 0, stacked(t, 1, holes, board))
+
+	act(t, h, Action{Type: Call}, Action{Type: Call}, Action{Type: Raise, Amount: 20}, Action{Type: Call}, Action{Type: Call})
+	for h.Street() != Showdown {
+		act(t, h, Action{Type: Check})
+	}
+
+	// 63 split two ways: the odd chip goes to the first winner left of the button.
+	if fmt.Sprint(h.Winnings()) != "[31 32 0]" {
+		t.Errorf("Winnings() == %v but expected [31 32 0]", h.Winnings())
+	}
+}
+
+func TestRaiseSizes(t *testing.T) {
+	h, _ := NewHand(Config{SmallBlind: 1, BigBlind: 2}, seats(100, 100, 100), 0, ordered())
+
+	tests := []struct {
+		action      Action
+		expectedErr string
+	}{
+		{Action{Type: Raise, Amount: 3}, "Invalid action: raise must be from 4 to 100"},
+		{Action{Type: Raise, Amount: 101}, "Invalid action: raise must be from 4 to 100"},
+		{Action{Type: Bet, Amount: 10}, "Invalid action: there is already a bet, so raise"},
+		{Action{Type: Check}, "Invalid action: Player 1 must call 2 or fold"},
+		{Action{Type: "limp"}, "Invalid action: unknown action \"limp\""},
+	}
+	for _, test := range tests {
+		if err := h.Act(test.action); err == nil || err.Error() != test.expectedErr {
+			t.Errorf("Act(%v) err == %v but expected %q", test.action, err, test.expectedErr)
+		}
+	}
+
+	act(t, h, Action{Type: Raise, Amount: 6})
+	if h.Options().MinRaise != 10 {
+		t.Errorf("after a raise to 6 MinRaise == %d but expected 10", h.Options().MinRaise)
+	}
+	act(t, h, Action{Type: Raise, Amount: 20})
+	if h.Options().MinRaise != 34 {
+		t.Errorf("after a raise to 20 MinRaise == %d but expected 34", h.Options().MinRaise)
+	}
+}
+
+func TestIncompleteAllInDoesNotReopen(t *testing.T) {
+	h, _ := NewHand(Config{SmallBlind: 5, BigBlind: 10}, seats(1000, 1000, 130), 0, ordered())
+
+	// Seat 0 raises to 100 and seat 1 calls; the short stack goes all in for
+	// 130, 30 more, which is less than a full raise of 90.
+	act(t, h, Action{Type: Raise, Amount: 100}, Action{Type: Call}, Action{Type: Raise, Amount: 130})
+
+	if h.ToAct() != 0 {
+		t.Fatalf("seat %d to act but expected seat 0", h.ToAct())
+	}
+	if options := h.Options(); options.Call != 30 || options.MinRaise != 0 {
+		t.Errorf("Options() == %+v but expected seat 0 only to call 30 or fold", options)
+	}
+	if err := h.Act(Action{Type: Raise, Amount: 300}); err == nil {
+		t.Errorf("Act(raise) err == nil but expected the betting not to be reopened")
+	}
+	act(t, h, Action{Type: Call}, Action{Type: Call})
+	if h.Street() != Flop || h.ToAct() != 1 {
+		t.Errorf("street %d with seat %d to act but expected the flop with seat 1 first", h.Street(), h.ToAct())
+	}
+}
+
+func TestPotLimit(t *testing.T) {
+	h, _ := NewHand(Config{SmallBlind: 1, BigBlind: 2, Limit: betting.PotLimit}, seats(100, 100, 100), 0, ordered())
+
+	if options := h.Options(); options.MinRaise != 4 || options.MaxRaise != 7 {
+		t.Errorf("Options() == %+v but expected a raise from 4 to 7", options)
+	}
+	act(t, h, Action{Type: Raise, Amount: 7}, Action{Type: Call})
+	// The big blind calls 5 into 16, so the pot raise is 5 + 21 to 28.
+	if options := h.Options(); options.MaxRaise != 28 {
+		t.Errorf("Options() == %+v but expected a raise up to 28", options)
+	}
+	if err := h.Act(Action{Type: Raise, Amount: 29}); err == nil {
+		t.Errorf("Act(raise to 29) err == nil but expected more than the pot to be refused")
+	}
+	act(t, h, Action{Type: Call})
+	if options := h.Options(); options.MinRaise != 2 || options.MaxRaise != 21 {
+		t.Errorf("Options() on the flop == %+v but expected a bet from 2 to 21", options)
+	}
+}
+
+func TestFixedLimit(t *testing.T) {
+	h, _ := NewHand(Config{SmallBlind: 1, BigBlind: 2, Limit: betting.FixedLimit, Cap: 4}, seats(100, 100, 100), 0, ordered())
+
+	act(t, h, Action{Type: Raise, Amount: 4}, Action{Type: Raise, Amount: 6})
+	if options := h.Options(); options.MinRaise != 8 || options.MaxRaise != 8 {
+		t.Errorf("Options() == %+v but expected a raise to 8 only", options)
+	}
+	if err := h.Act(Action{Type: Raise, Amount: 10}); err == nil {
+		t.Errorf("Act(raise to 10) err == nil but expected only a raise of one bet")
+	}
+	act(t, h, Action{Type: Raise, Amount: 8})
+	// The big blind and three raises make the cap.
+	if options := h.Options(); options.Call != 4 || options.MinRaise != 0 {
+		t.Errorf("Options() == %+v after the cap but expected only a call of 4", options)
+	}
+	act(t, h, Action{Type: Call}, Action{Type: Call})
+
+	act(t, h, Action{Type: Check}, Action{Type: Check}, Action{Type: Check})
+	if options := h.Options(); h.Street() != Turn || options.MinRaise != 4 {
+		t.Errorf("Options() on street %d == %+v but expected a big bet of 4 on the turn", h.Street(), options)
+	}
+}
+
+func TestAllInRunsOutTheBoard(t *testing.T) {
+	h, _ := NewHand(Config{SmallBlind: 5, BigBlind: 10}, seats(500, 200), 0, ordered())
+
+	act(t, h, Action{Type: Raise, Amount: 500}, Action{Type: Call})
+
+	if !h.Done() || len(h.Board()) != 5 {
+		t.Fatalf("Done() == %t with board %v but expected the board to be run out", h.Done(), h.Board())
+	}
+	events := h.Events()
+	returned := false
+	for _, event := range events {
+		if event.Type == Return && event.Seat == 0 && event.Amount == 300 {
+			returned = true
+		}
+	}
+	if !returned {
+		t.Errorf("Events() == %v but expected the uncalled 300 to be returned to seat 0", events)
+	}
+	total := 0
+	for _, p := range h.Players() {
+		total += p.Stack
+	}
+	if total != 700 {
+		t.Errorf("stacks add up to %d after the hand but expected 700", total)
+	}
+}
+
+func TestSidePots(t *testing.T) {
+	holes := [][]string{{"AS", "AC"}, {"KS", "KC"}, {"QS", "QC"}}
+	board := []string{"7H", "8H", "2C", "3S", "9D"}
+	h, _ := NewHand(Config{SmallBlind: 5, BigBlind: 10}, seats(100, 300, 500), 0, stacked(t, 1, holes, board))
+
+	// The aces are all in for 100 and the kings for 300; the queens cover both.
+	act(t, h, Action{Type: Raise, Amount: 100}, Action{Type: Raise, Amount: 300}, Action{Type: Call})
+
+	if !h.Done() {
+		t.Fatalf("Done() == false but expected the board to be run out")
+	}
+	if fmt.Sprint(h.Pots()) != "[{300 [0 1 2]} {400 [1 2]}]" {
+		t.Errorf("Pots() == %v but expected a main pot of 300 and a side pot of 400", h.Pots())
+	}
+	if fmt.Sprint(h.Winnings()) != "[300 400 0]" {
+		t.Errorf("Winnings() == %v but expected the aces to win the main pot and the kings the side pot", h.Winnings())
+	}
+	if h.Players()[2].Stack != 200 {
+		t.Errorf("queens stack == %d but expected 200", h.Players()[2].Stack)
+	}
+}
+
+func TestOddChipByHighestSuit(t *testing.T) {
+	holes := [][]string{{"AC", "2C"}, {"AS", "3C"}, {"7C", "8S"}}
+	board := []string{"KH", "QH", "JD", "TC", "5S"}
+	config := Config{SmallBlind: 5, BigBlind: 10, Ante: 1, OddChip: pot.HighestSuit}
+	h, _ := NewHand(config, seats(1000, 1000, 1000), 1, stacked(t, 2, holes, board))
+
+	act(t, h, Action{Type: Call}, Action{Type: Call}, Action{Type: Raise, Amount: 20}, Action{Type: Call}, Action{Type: Call})
+	for h.Street() != Showdown {
+		act(t, h, Action{Type: Check})
+	}
+
+	// Left of the button the odd chip would go to seat 0; the ace of spades takes it.
+	if fmt.Sprint(h.Winnings()) != "[31 32 0]" {
+		t.Errorf("Winnings() == %v but expected [31 32 0]", h.Winnings())
+	}
+}
+
+func TestOmaha(t *testing.T) {
+	// Seat 0 holds one heart, so with four on the board it has no flush;
+	// seat 1's pair of deuces wins.
+	holes := [][]string{{"AH", "KS", "QD", "JC"}, {"2C", "2S", "7S", "8S"}}
+	board := []string{"3H", "5H", "9H", "TH", "4C"}
+	config := Config{Variant: Omaha, SmallBlind: 5, BigBlind: 10, Limit: betting.PotLimit}
+	h, _ := NewHand(config, seats(1000, 1000), 0, stacked(t, 0, holes, board))
+
+	if len(h.Players()[0].Hole) != 4 {
+		t.Fatalf("seat 0 dealt %v but expected four hole cards", h.Players()[0].Hole)
+	}
+	act(t, h, Action{Type: Call}, Action{Type: Check})
+	for street := Flop; street <= River; street++ {
+		act(t, h, Action{Type: Check}, Action{Type: Check})
+	}
+	if fmt.Sprint(h.Winnings()) != "[0 20]" {
+		t.Errorf("Winnings() == %v but expected seat 1 to win 20", h.Winnings())
+	}
+}
+
+func TestOmahaHiLo(t *testing.T) {
+	// Seat 0 has trip kings for high and no low; seat 1 has 8-5-4-2-A.
+	holes := [][]string{{"KD", "KC", "QH", "QD"}, {"AS", "2S", "9D", "9C"}}
+	board := []string{"4H", "5C", "8S", "KS", "JD"}
+	config := Config{Variant: OmahaHiLo, SmallBlind: 5, BigBlind: 10, Limit: betting.FixedLimit}
+	h, _ := NewHand(config, seats(1000, 1000), 0, stacked(t, 0, holes, board))
+
+	act(t, h, Action{Type: Call}, Action{Type: Check})
+	for street := Flop; street <= River; street++ {
+		act(t, h, Action{Type: Check}, Action{Type: Check})
+	}
+	if fmt.Sprint(h.Winnings()) != "[10 10]" {
+		t.Errorf("Winnings() == %v but expected the pot split high and low", h.Winnings())
+	}
+}
+
+func TestView(t *testing.T) {
+	h, _ := NewHand(Config{SmallBlind: 1, BigBlind: 2}, seats(100, 100, 100), 0, ordered())
+
+	view := h.View(1)
+	if fmt.Sprint(view.Hole) != "[2H 5H]" || fmt.Sprint(view.Players[1].Hole) != "[2H 5H]" {
+		t.Errorf("View(1) holds %v but expected its own [2H 5H]", view.Hole)
+	}
+	if len(view.Players[0].Hole) != 0 || len(view.Players[2].Hole) != 0 {
+		t.Errorf("View(1) shows %v and %v but expected other hole cards hidden", view.Players[0].Hole, view.Players[2].Hole)
+	}
+	if view.Pot != 3 || view.Options != (Options{}) || h.View(0).Options.Call != 2 {
+		t.Errorf("View(1) has pot %d and options %+v but expected 3 and options only for seat 0 to act", view.Pot, view.Options)
+	}
+
+	act(t, h, Action{Type: Fold}, Action{Type: Call}, Action{Type: Check})
+	for street := Flop; street <= River; street++ {
+		act(t, h, Action{Type: Check}, Action{Type: Check})
+	}
+	view = h.View(1)
+	if len(view.Players[0].Hole) != 0 || fmt.Sprint(view.Players[2].Hole) != "[3H 6H]" {
+		t.Errorf("View(1) after the showdown shows %v and %v but expected only seat 2's [3H 6H]", view.Players[0].Hole, view.Players[2].Hole)
+	}
+}
+
+// ordered is a deck that deals the cards in order, hearts first.
+func ordered() *deck.Deck {
+	d, _ := deck.NewStacked(nil)
+	return d
+}
@@ -0,0 +1,196 @@
+package icm // github.com/sildani/poker-hands-go/icm
+
+import (
+	"errors"
+	"github.com/sildani/poker-hands-go/deck"
+	"math"
+	"sort"
+)
+
+// MaxExact is the most players Equities works out exactly. The recursion
+// visits every subset of players, so larger fields are sampled.
+const MaxExact = 16
+
+// Samples is how many finishing orders Equities samples for large fields.
+const Samples = 100000
+
+// Equities is each player's share of the prize pool under the Independent
+// Chip Model: a player finishes first with the chance of their share of
+// the chips, and so on down with the chips of the players left. payouts
+// are the prizes from first place down; places past the last payout or
+// the last player pay nothing. rng is only used for fields over MaxExact.
+func Equities(stacks []int, payouts []int, rng deck.RNG) ([]float64, error) {
+	if err := validate(stacks, payouts); err != nil {
+		return nil, err
+	}
+	if len(stacks) <= MaxExact {
+		return Exact(stacks, payouts), nil
+	}
+	return Sample(stacks, payouts, Samples, rng), nil
+}
+
+// Exact works out the equities by going through the chance of each set of
+// players taking the top places.
+func Exact(stacks []int, payouts []int) []float64 {
+	n := len(stacks)
+	places := min(len(payouts), n)
+	equities := make([]float64, n)
+
+	sums := make([]int, 1<<uint(n))
+	chances := make([]float64, 1<<uint(n))
+	chances[0] = 1
+	total := 0
+	for _, stack := range stacks {
+		total += stack
+	}
+
+	for mask := 0; mask < len(chances); mask++ {
+		place := 0
+		for i := 0; i < n; i++ {
+			if mask&(1<<uint(i)) != 0 {
+				sums[mask] = sums[mask&^(1<<uint(i))] + stacks[i]
+				place++
+			}
+		}
+		if chances[mask] == 0 || place >= places {
+			continue
+		}
+
+		remaining := float64(total - sums[mask])
+		for i := 0; i < n; i++ {
+			if mask&(1<<uint(i)) == 0 {
+				chance := chances[mask] * float64(stacks[i]) / remaining
+				equities[i] += chance * float64(payouts[place])
+				chances[mask|1<<uint(i)] += chance
+			}
+		}
+	}
+	return equities
+}
+
+// Sample estimates the equities from samples finishing orders drawn the
+// same way.
+func Sample(stacks []int, payouts []int, samples int, rng deck.RNG) []float64 {
+	n := len(stacks)
+	places := min(len(payouts), n)
+	equities := make([]float64, n)
+
+	for s := 0; s < samples; s++ {
+		left := append([]int{}, stacks...)
+		remaining := 0
+		for _, stack := range stacks {
+			remaining += stack
+		}
+		for place := 0; place < places; place++ {
+			pick := rng.Intn(remaining)
+			for i, stack := range left {
+				if pick < stack {
+					equities[i] += float64(payouts[place])
+					remaining -= stack
+					left[i] = 0
+					break
+				}
+				pick -= stack
+			}
+		}
+	}
+
+	for i := range equities {
+		equities[i] /= float64(samples)
+	}
+	return equities
+}
+
+// ICMDeal splits the prize pool left by ICM equity, in whole units.
+func ICMDeal(stacks []int, payouts []int, rng deck.RNG) ([]int, error) {
+	equities, err := Equities(stacks, payouts, rng)
+	if err != nil {
+		return nil, err
+	}
+	return DealFrom(stacks, payouts, equities)
+}
+
+// DealFrom rounds equities already worked out by Equities to the ICM deal,
+// so a sampled deal matches the equities shown beside it.
+func DealFrom(stacks []int, payouts []int, equities []float64) ([]int, error) {
+	if err := validate(stacks, payouts); err != nil {
+		return nil, err
+	}
+	if len(equities) != len(stacks) {
+		return nil, errors.New("Invalid equities: must have one for each player")
+	}
+	return round(equities, pool(stacks, payouts)), nil
+}
+
+// ChipChop gives everyone the prize for the last place paid among them,
+// then splits the rest of the pool by chips.
+func ChipChop(stacks []int, payouts []int) ([]int, error) {
+	if err := validate(stacks, payouts); err != nil {
+		return nil, err
+	}
+
+	places := min(len(payouts), len(stacks))
+	guaranteed := 0
+	if len(payouts) >= len(stacks) {
+		guaranteed = payouts[places-1]
+	}
+	total := 0
+	for _, stack := range stacks {
+		total += stack
+	}
+
+	rest := pool(stacks, payouts) - guaranteed*len(stacks)
+	shares := []float64{}
+	for _, stack := range stacks {
+		shares = append(shares, float64(guaranteed)+float64(rest)*float64(stack)/float64(total))
+	}
+	return round(shares, pool(stacks, payouts)), nil
+}
+
+func validate(stacks []int, payouts []int) error {
+	if len(stacks) < 2 {
+		return errors.New("Invalid stacks: must have at least two players")
+	}
+	for _, stack := range stacks {
+		if stack <= 0 {
+			return errors.New("Invalid stacks: every player must have chips")
+		}
+	}
+	if len(payouts) == 0 {
+		return errors.New("Invalid payouts: must pay at least one place")
+	}
+	for i, payout := range payouts {
+		if payout < 0 || (i > 0 && payout > payouts[i-1]) {
+			return errors.New("Invalid payouts: must not go up from first place down")
+		}
+	}
+	return nil
+}
+
+// pool is the prize money left for the players still in.
+func pool(stacks []int, payouts []int) int {
+	total := 0
+	for _, payout := range payouts[:min(len(payouts), len(stacks))] {
+		total += payout
+	}
+	return total
+}
+
+// round rounds shares down to whole units and hands what is left over one
+// unit at a time to the largest remainders, so they add up to total.
+func round(shares []float64, total int) []int {
+	rounded := []int{}
+	order := []int{}
+	for i, share := range shares {
+		rounded = append(rounded, int(math.Floor(share)))
+		total -= rounded[i]
+		order = append(order, i)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return shares[order[i]]-math.Floor(shares[order[i]]) > shares[order[j]]-math.Floor(shares[order[j]])
+	})
+	for i := 0; i < total && i < len(order); i++ {
+		rounded[order[i]]++
+	}
+	return rounded
+}
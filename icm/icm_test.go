@@ -0,0 +1,96 @@
+package icm // github.com/sildani/poker-hands-go/icm
+
+import (
+	"fmt"
+	"github.com/sildani/poker-hands-go/deck"
+	"math"
+	"testing"
+)
+
+func TestExact(t *testing.T) {
+	tests := []struct {
+		stacks           []int
+		payouts          []int
+		expectedEquities string
+	}{
+		{[]int{5000, 3000, 2000}, []int{50, 30, 20}, "[38.39 32.75 28.86]"},
+		// Heads up the second prize is locked up and the rest goes by chips.
+		{[]int{3000, 1000}, []int{70, 30}, "[60.00 40.00]"},
+		// Winner takes all is a chip count.
+		{[]int{5000, 3000, 2000}, []int{100}, "[50.00 30.00 20.00]"},
+		// Places past the last player pay nothing.
+		{[]int{1000, 1000}, []int{50, 30, 20}, "[40.00 40.00]"},
+	}
+
+	for _, test := range tests {
+		equities := fmt.Sprintf("%.2f", Exact(test.stacks, test.payouts))
+		if equities != test.expectedEquities {
+			t.Errorf("Exact(%v, %v) == %s but expected %s", test.stacks, test.payouts, equities, test.expectedEquities)
+		}
+	}
+}
+
+func TestSampleAgreesWithExact(t *testing.T) {
+	stacks := []int{4000, 2500, 1500, 1200, 800}
+	payouts := []int{500, 300, 200}
+	exact := Exact(stacks, payouts)
+	sampled := Sample(stacks, payouts, 50000, deck.NewSeededRNG(1))
+
+	for i := range stacks {
+		if math.Abs(exact[i]-sampled[i]) > 3 {
+			t.Errorf("Sample for seat %d == %.2f but Exact == %.2f", i, sampled[i], exact[i])
+		}
+	}
+
+	// Past MaxExact Equities samples, and still pays out the pool.
+	stacks = make([]int, MaxExact+1)
+	for i := range stacks {
+		stacks[i] = 1000 * (i + 1)
+	}
+	equities, err := Equities(stacks, payouts, deck.NewSeededRNG(1))
+	total := 0.0
+	for _, equity := range equities {
+		total += equity
+	}
+	if err != nil || math.Abs(total-1000) > 0.01 || equities[MaxExact] <= equities[0] {
+		t.Errorf("Equities for %d players == %v with err %v but expected the pool of 1000 shared by stack", len(stacks), equities, err)
+	}
+}
+
+func TestDeals(t *testing.T) {
+	stacks := []int{5000, 3000, 2000}
+	payouts := []int{50, 30, 20}
+
+	deal, err := ICMDeal(stacks, payouts, nil)
+	if err != nil || fmt.Sprint(deal) != "[38 33 29]" {
+		t.Errorf("ICMDeal == %v with err %v but expected [38 33 29]", deal, err)
+	}
+	if deal, err := DealFrom(stacks, payouts, []float64{38.4, 32.9, 28.7}); err != nil || fmt.Sprint(deal) != "[38 33 29]" {
+		t.Errorf("DealFrom == %v with err %v but expected [38 33 29]", deal, err)
+	}
+	if _, err := DealFrom(stacks, payouts, []float64{50, 50}); err == nil || err.Error() != "Invalid equities: must have one for each player" {
+		t.Errorf("DealFrom(two equities) err == %v but expected one for each player", err)
+	}
+
+	// Everyone is guaranteed 20 and the other 40 goes by chips.
+	chop, err := ChipChop(stacks, payouts)
+	if err != nil || fmt.Sprint(chop) != "[40 32 28]" {
+		t.Errorf("ChipChop == %v with err %v but expected [40 32 28]", chop, err)
+	}
+
+	tests := []struct {
+		stacks      []int
+		payouts     []int
+		expectedErr string
+	}{
+		{[]int{1000}, []int{100}, "Invalid stacks: must have at least two players"},
+		{[]int{1000, 0}, []int{100}, "Invalid stacks: every player must have chips"},
+		{[]int{1000, 1000}, []int{}, "Invalid payouts: must pay at least one place"},
+		{[]int{1000, 1000}, []int{30, 70}, "Invalid payouts: must not go up from first place down"},
+	}
+	for _, test := range tests {
+		if _, err := ChipChop(test.stacks, test.payouts); err == nil || err.Error() != test.expectedErr {
+			t.Errorf("ChipChop(%v, %v) err == %v but expected %q", test.stacks, test.payouts, err, test.expectedErr)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package isomorph // github.com/sildani/poker-hands-go/isomorph
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sildani/poker-hands-go/parser"
+	"sort"
+	"strings"
+)
+
+const maxRounds = 4
+
+var ranks = []string{"", "", "2", "3", "4", "5", "6", "7", "8", "9", "T", "J", "Q", "K", "A"}
+
+// Canonical suits are handed out in this order to the suits of a hand,
+// sorted by how many cards they hold in each round and then by rank.
+var canonicalSuits = []string{"S", "H", "D", "C"}
+
+var suitIndex = map[string]int{"S": 0, "H": 1, "D": 2, "C": 3}
+
+// key holds the rank mask of each canonical suit in each round, so two
+// hands that differ only by suits share a key.
+type key [4 * maxRounds]uint16
+
+// Indexer gives every canonical form of a round shape, such as []int{2}
+// for preflop or []int{2, 3} for the flop, a dense index. It enumerates the
+// forms up front: 169 preflop and 1,286,792 on the flop. The turn and river
+// have tens of millions and billions, so index those with Canonicalise and
+// a map instead.
+type Indexer struct {
+	shape []int
+	keys  []key
+}
+
+// Canonicalise maps rounds of cards (hole cards then board cards by round)
+// to the representative of every hand that differs only by suits.
+func Canonicalise(rounds [][]string) ([][]string, error) {
+	k, err := keyOf(rounds)
+	if err != nil {
+		return nil, err
+	}
+	return cardsOf(k, shapeOf(rounds)), nil
+}
+
+func NewIndexer(shape []int) (*Indexer, error) {
+	total := 0
+	for _, cards := range shape {
+		total += cards
+	}
+	if len(shape) == 0 || len(shape) > maxRounds || total > 52 {
+		return nil, fmt.Errorf("Invalid shape: must have one to %d rounds of at most 52 cards", maxRounds)
+	}
+
+	indexer := &Indexer{shape: append([]int{}, shape...)}
+	indexer.enumerate(0, make([]int, len(shape)), nil, nil, key{})
+	sort.Slice(indexer.keys, func(i, j int) bool {
+		return less(indexer.keys[i][:], indexer.keys[j][:])
+	})
+	return indexer, nil
+}
+
+func (x *Indexer) Size() int {
+	return len(x.keys)
+}
+
+func (x *Indexer) Index(rounds [][]string) (int, error) {
+	if fmt.Sprint(shapeOf(rounds)) != fmt.Sprint(x.shape) {
+		return 0, fmt.Errorf("Invalid rounds: must have %v cards", x.shape)
+	}
+	k, err := keyOf(rounds)
+	if err != nil {
+		return 0, err
+	}
+	return sort.Search(len(x.keys), func(i int) bool {
+		return !less(x.keys[i][:], k[:])
+	}), nil
+}
+
+func (x *Indexer) Unindex(index int) ([][]string, error) {
+	if index < 0 || index >= len(x.keys) {
+		return nil, fmt.Errorf("Invalid index: must be between 0 and %d", len(x.keys)-1)
+	}
+	return cardsOf(x.keys[index], x.shape), nil
+}
+
+// enumerate gives each canonical suit in turn a count of cards per round
+// and then the ranks of those cards. Suits come in the order keyOf sorts
+// them, so every canonical form is reached exactly once.
+func (x *Indexer) enumerate(suit int, used []int, previousCounts []int, previousMasks []uint16, k key) {
+	if suit == len(canonicalSuits) {
+		for round, cards := range x.shape {
+			if used[round] != cards {
+				return
+			}
+		}
+		x.keys = append(x.keys, k)
+		return
+	}
+
+	counts := make([]int, len(x.shape))
+	var chooseCounts func(round int, cards int)
+	chooseCounts = func(round int, cards int) {
+		if round == len(x.shape) {
+			if previousCounts != nil && compare(counts, previousCounts) > 0 {
+				return
+			}
+			sameCounts := previousCounts != nil && compare(counts, previousCounts) == 0
+			masks := make([]uint16, len(x.shape))
+			var chooseMasks func(round int, taken uint16)
+			chooseMasks = func(round int, taken uint16) {
+				if round == len(x.shape) {
+					if sameCounts && less(previousMasks, masks) {
+						return
+					}
+					next := k
+					nextUsed := make([]int, len(used))
+					for r, mask := range masks {
+						next[suit*maxRounds+r] = mask
+						nextUsed[r] = used[r] + counts[r]
+					}
+					x.enumerate(suit+1, nextUsed, append([]int{}, counts...), append([]uint16{}, masks...), next)
+					return
+				}
+				forEachMask(counts[round], taken, func(mask uint16) {
+					masks[round] = mask
+					chooseMasks(round+1, taken|mask)
+				})
+			}
+			chooseMasks(0, 0)
+			return
+		}
+		for count := 0; count <= x.shape[round]-used[round] && cards+count <= 13; count++ {
+			counts[round] = count
+			chooseCounts(round+1, cards+count)
+		}
+	}
+	chooseCounts(0, 0)
+}
+
+func keyOf(rounds [][]string) (key, error) {
+	if len(rounds) == 0 || len(rounds) > maxRounds {
+		return key{}, fmt.Errorf("Invalid rounds: must have one to %d rounds", maxRounds)
+	}
+
+	all := []string{}
+	for _, round := range rounds {
+		all = append(all, round...)
+	}
+	if len(all) == 0 {
+		return key{}, errors.New("Invalid rounds: must have at least one card")
+	}
+	if _, err := parser.ParseCards(strings.Join(all, " ")); err != nil {
+		return key{}, fmt.Errorf("Invalid rounds: %v", strings.TrimPrefix(err.Error(), "Invalid cards: "))
+	}
+
+	suits := make([][]uint16, len(canonicalSuits))
+	for suit := range suits {
+		suits[suit] = make([]uint16, len(rounds))
+	}
+	for round, cards := range rounds {
+		for _, card := range cards {
+			value, _ := parser.ParseCardValue(card[:1])
+			suits[suitIndex[card[1:]]][round] |= 1 << uint(value-2)
+		}
+	}
+
+	sort.Slice(suits, func(i, j int) bool {
+		if c := compare(countsOf(suits[i]), countsOf(suits[j])); c != 0 {
+			return c > 0
+		}
+		return less(suits[j], suits[i])
+	})
+
+	k := key{}
+	for suit, masks := range suits {
+		for round, mask := range masks {
+			k[suit*maxRounds+round] = mask
+		}
+	}
+	return k, nil
+}
+
+func cardsOf(k key, shape []int) [][]string {
+	rounds := make([][]string, len(shape))
+	for round := range shape {
+		rounds[round] = []string{}
+		for value := 14; value >= 2; value-- {
+			for suit, name := range canonicalSuits {
+				if k[suit*maxRounds+round]&(1<<uint(value-2)) != 0 {
+					rounds[round] = append(rounds[round], ranks[value]+name)
+				}
+			}
+		}
+	}
+	return rounds
+}
+
+func shapeOf(rounds [][]string) []int {
+	shape := []int{}
+	for _, round := range rounds {
+		shape = append(shape, len(round))
+	}
+	return shape
+}
+
+func countsOf(masks []uint16) []int {
+	counts := []int{}
+	for _, mask := range masks {
+		count := 0
+		for ; mask != 0; mask &= mask - 1 {
+			count++
+		}
+		counts = append(counts, count)
+	}
+	return counts
+}
+
+// forEachMask calls f with every set of count ranks not already taken.
+func forEachMask(count int, taken uint16, f func(mask uint16)) {
+	var choose func(value uint, count int, mask uint16)
+	choose = func(value uint, count int, mask uint16) {
+		if count == 0 {
+			f(mask)
+			return
+		}
+		for v := value; v+uint(count) <= 13; v++ {
+			if taken&(1<<v) == 0 {
+				choose(v+1, count-1, mask|1<<v)
+			}
+		}
+	}
+	choose(0, count, 0)
+}
+
+func compare(a []int, b []int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] > b[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
+
+func less(a []uint16, b []uint16) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package isomorph // github.com/sildani/poker-hands-go/isomorph
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestIndexerSize(t *testing.T) {
+	tests := []struct {
+		shape        []int
+		expectedSize int
+	}{
+		{[]int{1}, 13},
+		{[]int{2}, 169},
+		{[]int{1, 1}, 325},
+		{[]int{5}, 134459},
+		{[]int{2, 3}, 1286792},
+	}
+
+	for _, test := range tests {
+		indexer, err := NewIndexer(test.shape)
+		if err != nil {
+			t.Errorf("NewIndexer(%v) err == %q but expected nil", test.shape, err)
+			continue
+		}
+		if indexer.Size() != test.expectedSize {
+			t.Errorf("NewIndexer(%v).Size() == %d but expected %d", test.shape, indexer.Size(), test.expectedSize)
+		}
+	}
+}
+
+func TestNewIndexerInvalid(t *testing.T) {
+	for _, shape := range [][]int{{}, {2, 3, 1, 1, 1}, {30, 30}} {
+		if _, err := NewIndexer(shape); err == nil {
+			t.Errorf("NewIndexer(%v) err == nil but expected an error", shape)
+		}
+	}
+}
+
+func TestCanonicalise(t *testing.T) {
+	tests := []struct {
+		rounds   [][]string
+		expected [][]string
+	}{
+		{[][]string{{"AH", "KH"}}, [][]string{{"AS", "KS"}}},
+		{[][]string{{"KD", "AC"}}, [][]string{{"AS", "KH"}}},
+		{[][]string{{"2C", "2D"}}, [][]string{{"2S", "2H"}}},
+		// Hearts hold both hole cards, so they become spades
+		{[][]string{{"AH", "KH"}, {"2C", "3C", "4C"}}, [][]string{{"AS", "KS"}, {"4H", "3H", "2H"}}},
+		{[][]string{{"AD", "KC"}, {"2C", "3D", "4H"}}, [][]string{{"AS", "KH"}, {"4D", "3S", "2H"}}},
+	}
+
+	for _, test := range tests {
+		canonical, err := Canonicalise(test.rounds)
+		if err != nil {
+			t.Errorf("Canonicalise(%v) err == %q but expected nil", test.rounds, err)
+		}
+		if fmt.Sprint(canonical) != fmt.Sprint(test.expected) {
+			t.Errorf("Canonicalise(%v) == %v but expected %v", test.rounds, canonical, test.expected)
+		}
+	}
+}
+
+func TestCanonicaliseInvalid(t *testing.T) {
+	tests := []struct {
+		rounds      [][]string
+		expectedErr string
+	}{
+		{[][]string{}, "Invalid rounds: must have one to 4 rounds"},
+		{[][]string{{}}, "Invalid rounds: must have at least one card"},
+		{[][]string{{"AH", "AP"}}, "Invalid rounds: contains invalid card"},
+		{[][]string{{"AH", "KH"}, {"AH", "2C", "3C"}}, "Invalid rounds: contains duplicate card"},
+	}
+
+	for _, test := range tests {
+		_, err := Canonicalise(test.rounds)
+		if err == nil || err.Error() != test.expectedErr {
+			t.Errorf("Canonicalise(%v) err == %v but expected %q", test.rounds, err, test.expectedErr)
+		}
+	}
+}
+
+func TestIndexIgnoresSuitsAndOrder(t *testing.T) {
+	indexer, _ := NewIndexer([]int{2, 3})
+
+	tests := [][][]string{
+		{{"AH", "KH"}, {"2C", "3C", "4D"}},
+		{{"KS", "AS"}, {"4H", "3D", "2D"}},
+		{{"AC", "KC"}, {"3S", "2S", "4H"}},
+	}
+
+	expected, _ := indexer.Index(tests[0])
+	for _, rounds := range tests {
+		index, err := indexer.Index(rounds)
+		if err != nil {
+			t.Errorf("Index(%v) err == %q but expected nil", rounds, err)
+		}
+		if index != expected {
+			t.Errorf("Index(%v) == %d but expected %d", rounds, index, expected)
+		}
+	}
+
+	different, _ := indexer.Index([][]string{{"AH", "KH"}, {"2C", "3C", "4C"}})
+	if different == expected {
+		t.Errorf("Index gives the monotone flop the same index %d as the two-tone flop", expected)
+	}
+}
+
+func TestUnindex(t *testing.T) {
+	indexer, _ := NewIndexer([]int{2})
+
+	for index := 0; index < indexer.Size(); index++ {
+		rounds, err := indexer.Unindex(index)
+		if err != nil {
+			t.Errorf("Unindex(%d) err == %q but expected nil", index, err)
+			continue
+		}
+		again, err := indexer.Index(rounds)
+		if err != nil || again != index {
+			t.Errorf("Index(Unindex(%d)) == %d, %v but expected %d, nil", index, again, err, index)
+		}
+	}
+
+	if _, err := indexer.Unindex(indexer.Size()); err == nil {
+		t.Errorf("Unindex(%d) err == nil but expected an error", indexer.Size())
+	}
+	if _, err := indexer.Index([][]string{{"AH", "KH"}, {"2C", "3C", "4C"}}); err == nil {
+		t.Errorf("Index of a flop on a preflop indexer err == nil but expected an error")
+	}
+}
@@ -0,0 +1,290 @@
+package mixed // github.com/sildani/poker-hands-go/mixed
+
+import (
+	"errors"
+	"github.com/sildani/poker-hands-go/betting"
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/draw"
+	"github.com/sildani/poker-hands-go/holdem"
+	"github.com/sildani/poker-hands-go/stud"
+)
+
+// Action is a decision in any game. Discards only matter in draw games.
+type Action struct {
+	Type     string
+	Amount   int
+	Discards []string
+}
+
+type Seat struct {
+	Name  string
+	Stack int
+}
+
+// Game is one hand of any variant, played one Act at a time.
+type Game interface {
+	Variant() string
+	ToAct() int
+	Done() bool
+	Options() betting.Options
+	Act(action Action) error
+	Pot() int
+	Stacks() []int
+	Winnings() []int
+}
+
+// Variant deals hands of one game at set stakes.
+type Variant struct {
+	Name string
+	Deal func(seats []Seat, button int, d *deck.Deck) (Game, error)
+}
+
+// Holdem is a hold'em or Omaha variant, by config.Variant.
+func Holdem(name string, config holdem.Config) Variant {
+	return Variant{name, func(seats []Seat, button int, d *deck.Deck) (Game, error) {
+		holdemSeats := []holdem.Seat{}
+		for _, seat := range seats {
+			holdemSeats = append(holdemSeats, holdem.Seat{Name: seat.Name, Stack: seat.Stack})
+		}
+		h, err := holdem.NewHand(config, holdemSeats, button, d)
+		if err != nil {
+			return nil, err
+		}
+		return holdemGame{name, h}, nil
+	}}
+}
+
+// Stud is a stud, razz or stud hi-lo variant, by config.Variant. The
+// button seat deals.
+func Stud(name string, config stud.Config) Variant {
+	return Variant{name, func(seats []Seat, button int, d *deck.Deck) (Game, error) {
+		studSeats := []stud.Seat{}
+		for _, seat := range seats {
+			studSeats = append(studSeats, stud.Seat{Name: seat.Name, Stack: seat.Stack})
+		}
+		h, err := stud.NewHand(config, studSeats, button, d)
+		if err != nil {
+			return nil, err
+		}
+		return studGame{name, h}, nil
+	}}
+}
+
+// Draw is a five-card draw variant.
+func Draw(name string, config draw.Config) Variant {
+	return Variant{name, func(seats []Seat, button int, d *deck.Deck) (Game, error) {
+		drawSeats := []draw.Seat{}
+		for _, seat := range seats {
+			drawSeats = append(drawSeats, draw.Seat{Name: seat.Name, Stack: seat.Stack})
+		}
+		h, err := draw.NewHand(config, drawSeats, button, d)
+		if err != nil {
+			return nil, err
+		}
+		return drawGame{name, h}, nil
+	}}
+}
+
+// HORSE is fixed-limit hold'em, Omaha hi-lo, razz, stud and stud hi-lo at
+// the given small bet and twice that for the big bet. The blinds are half
+// the small bet and the small bet; stud games ante a fifth of the small
+// bet and bring it in for a quarter.
+func HORSE(smallBet int) []Variant {
+	blinds := holdem.Config{SmallBlind: max(smallBet/2, 1), BigBlind: smallBet, Limit: betting.FixedLimit}
+	studStakes := stud.Config{Ante: max(smallBet/5, 1), BringIn: max(smallBet/4, 1), SmallBet: smallBet, BigBet: 2 * smallBet}
+
+	holdemConfig, omahaConfig := blinds, blinds
+	omahaConfig.Variant = holdem.OmahaHiLo
+	razzConfig, studConfig, studHiLoConfig := studStakes, studStakes, studStakes
+	razzConfig.Variant = stud.Razz
+	studHiLoConfig.Variant = stud.StudHiLo
+
+	return []Variant{
+		Holdem("Hold'em", holdemConfig),
+		Holdem("Omaha Hi-Lo", omahaConfig),
+		Stud("Razz", razzConfig),
+		Stud("Stud", studConfig),
+		Stud("Stud Hi-Lo", studHiLoConfig),
+	}
+}
+
+type holdemGame struct {
+	name string
+	*holdem.Hand
+}
+
+func (g holdemGame) Variant() string {
+	return g.name
+}
+
+func (g holdemGame) Act(action Action) error {
+	return g.Hand.Act(holdem.Action{Type: action.Type, Amount: action.Amount})
+}
+
+func (g holdemGame) Stacks() []int {
+	stacks := []int{}
+	for _, p := range g.Players() {
+		stacks = append(stacks, p.Stack)
+	}
+	return stacks
+}
+
+type studGame struct {
+	name string
+	*stud.Hand
+}
+
+func (g studGame) Variant() string {
+	return g.name
+}
+
+func (g studGame) Act(action Action) error {
+	return g.Hand.Act(stud.Action{Type: action.Type, Amount: action.Amount})
+}
+
+func (g studGame) Stacks() []int {
+	stacks := []int{}
+	for _, p := range g.Players() {
+		stacks = append(stacks, p.Stack)
+	}
+	return stacks
+}
+
+type drawGame struct {
+	name string
+	*draw.Hand
+}
+
+func (g drawGame) Variant() string {
+	return g.name
+}
+
+func (g drawGame) Act(action Action) error {
+	return g.Hand.Act(draw.Action{Type: action.Type, Amount: action.Amount, Discards: action.Discards})
+}
+
+func (g drawGame) Stacks() []int {
+	stacks := []int{}
+	for _, p := range g.Players() {
+		stacks = append(stacks, p.Stack)
+	}
+	return stacks
+}
+
+// Rotation deals hand after hand, switching games after each orbit or
+// every Hands hands and carrying the stacks and button from one hand to
+// the next. Players with no chips left are dropped. The games go in order
+// unless Choose is set, for dealer's choice: it is given the button seat
+// and picks the index of the first game and each one after, wrapping
+// round Variants either way when it is out of range.
+type Rotation struct {
+	Variants []Variant
+	Hands    int
+	Choose   func(button int) int
+	seats    []Seat
+	button   int
+	game     int
+	played   int
+	orbit    int
+	started  bool
+	last     Game
+}
+
+func NewRotation(variants []Variant, hands int, seats []Seat, button int) (*Rotation, error) {
+	if len(variants) == 0 {
+		return nil, errors.New("Invalid rotation: must have at least one game")
+	}
+	if hands < 0 {
+		return nil, errors.New("Invalid rotation: hands per game must not be negative")
+	}
+	if button < 0 || button >= len(seats) {
+		return nil, errors.New("Invalid button: must be one of the seats")
+	}
+	return &Rotation{
+		Variants: variants,
+		Hands:    hands,
+		seats:    append([]Seat{}, seats...),
+		button:   button,
+		orbit:    len(seats),
+	}, nil
+}
+
+// Seats are the players still in and their stacks as of the hand last
+// dealt.
+func (r *Rotation) Seats() []Seat {
+	return append([]Seat{}, r.seats...)
+}
+
+// Button is the button seat for the hand last dealt.
+func (r *Rotation) Button() int {
+	return r.button
+}
+
+// Variant is the game of the hand last dealt.
+func (r *Rotation) Variant() Variant {
+	return r.Variants[r.game]
+}
+
+// Deal settles the last hand, which must be over, and deals the next.
+func (r *Rotation) Deal(d *deck.Deck) (Game, error) {
+	if r.last != nil {
+		if !r.last.Done() {
+			return nil, errors.New("Invalid deal: the last hand is not over")
+		}
+		r.finish(r.last.Stacks())
+		r.last = nil
+	} else if r.Choose != nil && !r.started {
+		r.game = r.choose()
+	}
+	if len(r.seats) < 2 {
+		return nil, errors.New("Invalid deal: fewer than two players have chips")
+	}
+
+	game, err := r.Variant().Deal(r.seats, r.button, d)
+	if err != nil {
+		return nil, err
+	}
+	r.last = game
+	r.started = true
+	return game, nil
+}
+
+// finish carries the stacks over, drops anyone with no chips, moves the
+// button and switches the game when it is due.
+func (r *Rotation) finish(stacks []int) {
+	seats := []Seat{}
+	button := r.button
+	for i, seat := range r.seats {
+		seat.Stack = stacks[i]
+		if seat.Stack > 0 {
+			seats = append(seats, seat)
+		} else if i <= r.button {
+			button--
+		}
+	}
+	r.seats = seats
+	if len(r.seats) == 0 {
+		return
+	}
+	r.button = (button + 1 + len(r.seats)) % len(r.seats)
+
+	r.played++
+	due := r.Hands
+	if due == 0 {
+		due = r.orbit
+	}
+	if r.played >= due {
+		r.played = 0
+		r.orbit = len(r.seats)
+		r.game = (r.game + 1) % len(r.Variants)
+		if r.Choose != nil {
+			r.game = r.choose()
+		}
+	}
+}
+
+// choose is the game Choose picks, wrapped into range.
+func (r *Rotation) choose() int {
+	n := len(r.Variants)
+	return (r.Choose(r.button)%n + n) % n
+}
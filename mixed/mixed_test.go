@@ -0,0 +1,112 @@
+package mixed // github.com/sildani/poker-hands-go/mixed
+
+import (
+	"fmt"
+	"github.com/sildani/poker-hands-go/betting"
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/draw"
+	"testing"
+)
+
+// play checks or folds the hand down, standing pat in the draw and
+// bringing it in in stud, where the bring-in may not fold.
+func play(t *testing.T, g Game) {
+	for !g.Done() {
+		action := Action{Type: "fold"}
+		if options := g.Options(); options == (betting.Options{}) {
+			action = Action{Type: "draw"}
+		} else if options.Check {
+			action = Action{Type: "check"}
+		}
+		err := g.Act(action)
+		if err != nil && action.Type == "fold" {
+			action = Action{Type: "call"}
+			err = g.Act(action)
+		}
+		if err != nil {
+			t.Fatalf("%s: Act(%v) err == %q but expected nil", g.Variant(), action, err)
+		}
+	}
+}
+
+func seats() []Seat {
+	return []Seat{{"Player 1", 1000}, {"Player 2", 1000}, {"Player 3", 1000}}
+}
+
+func TestRotationByOrbit(t *testing.T) {
+	r, _ := NewRotation(HORSE(10), 0, seats(), 0)
+	rng := deck.NewSeededRNG(1)
+
+	expected := []string{"Hold'em", "Hold'em", "Hold'em", "Omaha Hi-Lo", "Omaha Hi-Lo", "Omaha Hi-Lo",
+		"Razz", "Razz", "Razz", "Stud", "Stud", "Stud", "Stud Hi-Lo", "Stud Hi-Lo", "Stud Hi-Lo", "Hold'em"}
+	for i, name := range expected {
+		d := deck.New(rng)
+		d.Shuffle()
+		g, err := r.Deal(d)
+		if err != nil {
+			t.Fatalf("hand %d Deal err == %q but expected nil", i, err)
+		}
+		if g.Variant() != name || r.Button() != i%3 {
+			t.Errorf("hand %d dealt %s with the button on seat %d but expected %s on seat %d",
+				i, g.Variant(), r.Button(), name, i%3)
+		}
+		play(t, g)
+	}
+
+	total := 0
+	for _, seat := range r.Seats() {
+		total += seat.Stack
+	}
+	if total != 3000 {
+		t.Errorf("stacks add up to %d but expected 3000", total)
+	}
+}
+
+func TestRotationByHandsAndChoice(t *testing.T) {
+	variants := append(HORSE(10)[:1], Draw("Draw", draw.Config{SmallBlind: 5, BigBlind: 10}))
+	r, _ := NewRotation(variants, 2, seats(), 0)
+	r.Choose = func(button int) int {
+		return button + 1
+	}
+
+	expected := []string{"Draw", "Draw", "Draw", "Draw", "Hold'em", "Hold'em"}
+	for i, name := range expected {
+		d := deck.New(deck.NewSeededRNG(int64(i)))
+		d.Shuffle()
+		g, _ := r.Deal(d)
+		if g.Variant() != name {
+			t.Errorf("hand %d dealt %s but expected %s", i, g.Variant(), name)
+		}
+		if i == 0 {
+			if _, err := r.Deal(d); err == nil || err.Error() != "Invalid deal: the last hand is not over" {
+				t.Errorf("Deal with a hand in play err == %v but expected an error", err)
+			}
+		}
+		play(t, g)
+	}
+}
+
+func TestRotationChoiceOutOfRange(t *testing.T) {
+	r, _ := NewRotation(HORSE(10), 0, seats(), 0)
+	r.Choose = func(button int) int {
+		return -1 - 5*button
+	}
+
+	d := deck.New(deck.NewSeededRNG(1))
+	d.Shuffle()
+	if g, err := r.Deal(d); err != nil || g.Variant() != "Stud Hi-Lo" {
+		t.Errorf("Deal with Choose == -1 dealt %v, %v but expected the last game, Stud Hi-Lo", g, err)
+	}
+}
+
+func TestFinishDropsBustedPlayers(t *testing.T) {
+	r, _ := NewRotation(HORSE(10), 0, seats(), 0)
+	r.finish([]int{0, 1200, 1800})
+
+	if fmt.Sprint(r.Seats()) != "[{Player 2 1200} {Player 3 1800}]" || r.Button() != 0 {
+		t.Errorf("Seats() == %v with the button on %d but expected Player 2 on the button", r.Seats(), r.Button())
+	}
+	if _, err := NewRotation(nil, 0, seats(), 0); err == nil {
+		t.Errorf("NewRotation with no games err == nil but expected an error")
+	}
+}
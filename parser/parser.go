@@ -35,6 +35,19 @@ func ParseCardValue(s string) (int, error) {
 	}
 }
 
+func IsCardValid(card string) bool {
+	for _, validCard := range cards {
+		if card == validCard {
+			return true
+		}
+	}
+	return false
+}
+
+func Cards() []string {
+	return append([]string{}, cards[:]...)
+}
+
 func ParseHand(hand string) ([]string, error) {
 	parsedHand := strings.Split(hand, " ")
 
@@ -42,26 +55,39 @@ func ParseHand(hand string) ([]string, error) {
 		return []string{""}, errors.New("Invalid hand: must have five cards")
 	}
 
+	if err := validateCards(parsedHand); err != nil {
+		return []string{""}, fmt.Errorf("Invalid hand: %v", err)
+	}
+
+	return parsedHand, nil
+}
+
+func ParseCards(s string) ([]string, error) {
+	parsedCards := strings.Fields(s)
+
+	if len(parsedCards) == 0 {
+		return []string{""}, errors.New("Invalid cards: must have at least one card")
+	}
+
+	if err := validateCards(parsedCards); err != nil {
+		return []string{""}, fmt.Errorf("Invalid cards: %v", err)
+	}
+
+	return parsedCards, nil
+}
+
+func validateCards(parsedCards []string) error {
 	cardsSeen := make(map[string]int)
 
-	for _, card := range parsedHand {
-		isCardValid := false
-		for _, validCard := range cards {
-			if !isCardValid {
-				if card == validCard {
-					isCardValid = true
-				}
-			}
-		}
-		if !isCardValid {
-			return []string{""}, fmt.Errorf("Invalid hand: contains invalid card")
+	for _, card := range parsedCards {
+		if !IsCardValid(card) {
+			return errors.New("contains invalid card")
 		}
 		if cardsSeen[card] != 0 {
-			return []string{""}, fmt.Errorf("Invalid hand: contains duplicate card")
-		} else {
-			cardsSeen[card] += 1
+			return errors.New("contains duplicate card")
 		}
+		cardsSeen[card] += 1
 	}
 
-	return parsedHand, nil
+	return nil
 }
@@ -53,7 +53,7 @@ func TestParseCardValueInvalidCardValue(t *testing.T) {
 			}
 		}
 		if parsedValue != test.expectedParsedValue {
-			t.Errorf("ParseCardValue(%q) parsedValue == %q but expected %q",
+			t.Errorf("ParseCardValue(%q) parsedValue == %d but expected %d",
 				test.value, parsedValue, test.expectedParsedValue)
 		}
 	}
@@ -85,7 +85,7 @@ func TestParseCardValueValidCardValue(t *testing.T) {
 			t.Errorf("ParseHand(%q) err == %q but expected nil", test.value, err)
 		}
 		if parsedValue != test.expectedParsedValue {
-			t.Errorf("ParseCardValue(%q) == %t but expected %t",
+			t.Errorf("ParseCardValue(%q) == %d but expected %d",
 				test.value, parsedValue, test.expectedParsedValue)
 		}
 	}
@@ -203,3 +203,76 @@ func TestParseHandValidHand(t *testing.T) {
 		}
 	}
 }
+
+func TestIsCardValid(t *testing.T) {
+	var tests = []struct {
+		card             string
+		expectedValidity bool
+	}{
+		{"2H", true},
+		{"TD", true},
+		{"AS", true},
+		{"KC", true},
+		{"", false},
+		{"1H", false},
+		{"10H", false},
+		{"AP", false},
+		{"ah", false},
+	}
+
+	for _, test := range tests {
+		validity := IsCardValid(test.card)
+		if validity != test.expectedValidity {
+			t.Errorf("IsCardValid(%q) == %t but expected %t", test.card, validity, test.expectedValidity)
+		}
+	}
+}
+
+func TestCards(t *testing.T) {
+	deck := Cards()
+	if len(deck) != 52 {
+		t.Errorf("len(Cards()) == %d but expected 52", len(deck))
+	}
+
+	deck[0] = "XX"
+	if Cards()[0] != "2H" {
+		t.Errorf("Cards()[0] == %q after modifying a copy but expected %q", Cards()[0], "2H")
+	}
+}
+
+func TestParseCards(t *testing.T) {
+	var tests = []struct {
+		cards         string
+		expectedCards []string
+		expectedErr   string
+	}{
+		{"AH", []string{"AH"}, ""},
+		{"2H 3D 4S", []string{"2H", "3D", "4S"}, ""},
+		{" 2H  3D 4S 5C ", []string{"2H", "3D", "4S", "5C"}, ""},
+		{"2H 3D 4S 5C 6H 7D 8S", []string{"2H", "3D", "4S", "5C", "6H", "7D", "8S"}, ""},
+		{"", []string{""}, "Invalid cards: must have at least one card"},
+		{"   ", []string{""}, "Invalid cards: must have at least one card"},
+		{"2H 3D 4P", []string{""}, "Invalid cards: contains invalid card"},
+		{"2H 3D 2H", []string{""}, "Invalid cards: contains duplicate card"},
+	}
+
+	for _, test := range tests {
+		parsedCards, err := ParseCards(test.cards)
+		if test.expectedErr == "" && err != nil {
+			t.Errorf("ParseCards(%q) err == %q but expected nil", test.cards, err)
+		}
+		if test.expectedErr != "" && (err == nil || err.Error() != test.expectedErr) {
+			t.Errorf("ParseCards(%q) err == %v but expected %q", test.cards, err, test.expectedErr)
+		}
+		if len(parsedCards) != len(test.expectedCards) {
+			t.Errorf("ParseCards(%q) == %q but expected %q", test.cards, parsedCards, test.expectedCards)
+			continue
+		}
+		for i, card := range test.expectedCards {
+			if parsedCards[i] != card {
+				t.Errorf("ParseCards(%q) == %q but expected %q", test.cards, parsedCards, test.expectedCards)
+				break
+			}
+		}
+	}
+}
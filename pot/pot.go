@@ -0,0 +1,142 @@
+package pot // github.com/sildani/poker-hands-go/pot
+
+import (
+	"github.com/sildani/poker-hands-go/parser"
+	"sort"
+)
+
+// Rules for who gets the odd chips when a pot does not split evenly.
+const LeftOfButton = "left of button"
+const HighestSuit = "highest suit"
+
+var suitRanks = map[string]int{"C": 0, "D": 1, "H": 2, "S": 3}
+
+type Pot struct {
+	Amount   int
+	Eligible []int
+}
+
+// Build splits what each seat put in into the main pot and side pots, one
+// for each level a player is all in at. Folded seats' chips go in the pots
+// but they are not eligible to win them.
+func Build(contributions []int, folded []bool) []Pot {
+	remaining := append([]int{}, contributions...)
+	pots := []Pot{}
+
+	for {
+		level := 0
+		for seat, amount := range remaining {
+			if !folded[seat] && amount > 0 && (level == 0 || amount < level) {
+				level = amount
+			}
+		}
+		if level == 0 {
+			break
+		}
+
+		p := Pot{Eligible: []int{}}
+		for seat, amount := range remaining {
+			taken := min(amount, level)
+			p.Amount += taken
+			remaining[seat] -= taken
+			if !folded[seat] && taken == level {
+				p.Eligible = append(p.Eligible, seat)
+			}
+		}
+		pots = append(pots, p)
+	}
+
+	// Chips from folded seats above everyone still in go to the last pot.
+	for _, amount := range remaining {
+		if amount > 0 && len(pots) > 0 {
+			pots[len(pots)-1].Amount += amount
+		}
+	}
+	return pots
+}
+
+// Award gives each pot to its eligible seats with the highest score,
+// splitting ties. Odd chips go one at a time to the tied winners in the
+// order of oddChip: the first left of button, or the one holding the
+// highest card, ranked by value and then suit (spades, hearts, diamonds,
+// clubs). It returns what each seat wins.
+func Award(pots []Pot, scores []int, cards [][]string, button int, oddChip string) []int {
+	winnings := make([]int, len(scores))
+
+	for _, p := range pots {
+		winners := []int{}
+		for _, seat := range p.Eligible {
+			if len(winners) == 0 || scores[seat] > scores[winners[0]] {
+				winners = []int{seat}
+			} else if scores[seat] == scores[winners[0]] {
+				winners = append(winners, seat)
+			}
+		}
+		if len(winners) == 0 {
+			continue
+		}
+
+		if oddChip == HighestSuit {
+			sort.Slice(winners, func(i, j int) bool {
+				return highestCard(cards[winners[i]]) > highestCard(cards[winners[j]])
+			})
+		} else {
+			sort.Slice(winners, func(i, j int) bool {
+				return (winners[i]-button+len(scores)-1)%len(scores) < (winners[j]-button+len(scores)-1)%len(scores)
+			})
+		}
+
+		for i, seat := range winners {
+			winnings[seat] += p.Amount / len(winners)
+			if i < p.Amount%len(winners) {
+				winnings[seat]++
+			}
+		}
+	}
+
+	return winnings
+}
+
+// AwardSplit is Award for hi-lo games: each pot is split between the
+// highest score in high and the highest in low, the odd chip going high.
+// A low score of 0 does not qualify, and with no qualifying low the high
+// hand takes the whole pot.
+func AwardSplit(pots []Pot, high []int, low []int, cards [][]string, button int, oddChip string) []int {
+	winnings := make([]int, len(high))
+
+	for _, p := range pots {
+		qualified := false
+		for _, seat := range p.Eligible {
+			if low[seat] > 0 {
+				qualified = true
+			}
+		}
+
+		halves := []Pot{p}
+		if qualified {
+			halves = []Pot{{p.Amount - p.Amount/2, p.Eligible}, {p.Amount / 2, p.Eligible}}
+		}
+		for i, half := range halves {
+			scores := high
+			if i == 1 {
+				scores = low
+			}
+			for seat, amount := range Award([]Pot{half}, scores, cards, button, oddChip) {
+				winnings[seat] += amount
+			}
+		}
+	}
+
+	return winnings
+}
+
+func highestCard(cards []string) int {
+	highest := 0
+	for _, card := range cards {
+		value, _ := parser.ParseCardValue(card[:1])
+		if rank := value*4 + suitRanks[card[1:]]; rank > highest {
+			highest = rank
+		}
+	}
+	return highest
+}
@@ -0,0 +1,92 @@
+package pot // github.com/sildani/poker-hands-go/pot
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		contributions []int
+		folded        []bool
+		expectedPots  string
+	}{
+		// Everyone in for the same amount makes one pot.
+		{[]int{100, 100, 100}, []bool{false, false, false}, "[{300 [0 1 2]}]"},
+		// A short all in makes a main pot and a side pot.
+		{[]int{50, 100, 100}, []bool{false, false, false}, "[{150 [0 1 2]} {100 [1 2]}]"},
+		// Three different all ins.
+		{[]int{25, 50, 100, 100}, []bool{false, false, false, false}, "[{100 [0 1 2 3]} {75 [1 2 3]} {100 [2 3]}]"},
+		// Folded chips go in the pots without making the folder eligible.
+		{[]int{20, 50, 100, 100}, []bool{true, false, false, false}, "[{170 [1 2 3]} {100 [2 3]}]"},
+		{[]int{80, 50, 100, 100}, []bool{true, false, false, false}, "[{200 [1 2 3]} {130 [2 3]}]"},
+		// Chips folded above everyone still in go to the last pot.
+		{[]int{100, 50, 50}, []bool{true, false, false}, "[{200 [1 2]}]"},
+		{[]int{0, 0, 0}, []bool{false, false, false}, "[]"},
+	}
+
+	for _, test := range tests {
+		pots := Build(test.contributions, test.folded)
+		if fmt.Sprint(pots) != test.expectedPots {
+			t.Errorf("Build(%v, %v) == %v but expected %s", test.contributions, test.folded, pots, test.expectedPots)
+		}
+	}
+}
+
+func TestAward(t *testing.T) {
+	cards := [][]string{{"AS", "2C"}, {"AC", "3C"}, {"AD", "4C"}, {"AH", "5C"}}
+
+	tests := []struct {
+		name             string
+		pots             []Pot
+		scores           []int
+		button           int
+		oddChip          string
+		expectedWinnings string
+	}{
+		{"best hand takes the pot", []Pot{{300, []int{0, 1, 2}}}, []int{1, 3, 2, 0}, 0, LeftOfButton, "[0 300 0 0]"},
+		{"short stack wins the main pot only", []Pot{{150, []int{0, 1, 2}}, {100, []int{1, 2}}},
+			[]int{3, 2, 1, 0}, 0, LeftOfButton, "[150 100 0 0]"},
+		{"ineligible best hand is skipped", []Pot{{100, []int{1, 2}}}, []int{9, 1, 2, 0}, 0, LeftOfButton, "[0 0 100 0]"},
+		{"tie splits evenly", []Pot{{100, []int{0, 1, 2}}}, []int{2, 2, 1, 0}, 0, LeftOfButton, "[50 50 0 0]"},
+		{"odd chip left of the button", []Pot{{101, []int{0, 1, 2}}}, []int{2, 2, 1, 0}, 0, LeftOfButton, "[50 51 0 0]"},
+		{"odd chip wraps around the button", []Pot{{101, []int{0, 1, 2}}}, []int{2, 2, 1, 0}, 1, LeftOfButton, "[51 50 0 0]"},
+		{"odd chips go one each", []Pot{{302, []int{0, 1, 2, 3}}}, []int{2, 2, 2, 2}, 3, LeftOfButton, "[76 76 75 75]"},
+		{"odd chip to the highest suit", []Pot{{101, []int{0, 1, 2, 3}}}, []int{2, 2, 1, 1}, 0, HighestSuit, "[51 50 0 0]"},
+		{"odd chips by suit", []Pot{{103, []int{0, 1, 2, 3}}}, []int{2, 2, 2, 2}, 0, HighestSuit, "[26 25 26 26]"},
+	}
+
+	for _, test := range tests {
+		winnings := Award(test.pots, test.scores, cards, test.button, test.oddChip)
+		if fmt.Sprint(winnings) != test.expectedWinnings {
+			t.Errorf("%s: Award(%v, %v) == %v but expected %s", test.name, test.pots, test.scores, winnings, test.expectedWinnings)
+		}
+	}
+}
+
+func TestAwardSplit(t *testing.T) {
+	cards := [][]string{{"AS", "2C"}, {"AC", "3C"}, {"AD", "4C"}}
+
+	tests := []struct {
+		name             string
+		pots             []Pot
+		high             []int
+		low              []int
+		expectedWinnings string
+	}{
+		{"high and low split", []Pot{{100, []int{0, 1, 2}}}, []int{3, 2, 1}, []int{0, 2, 1}, "[50 50 0]"},
+		{"odd chip goes high", []Pot{{101, []int{0, 1, 2}}}, []int{3, 2, 1}, []int{0, 2, 1}, "[51 50 0]"},
+		{"no low takes it all", []Pot{{100, []int{0, 1, 2}}}, []int{3, 2, 1}, []int{0, 0, 0}, "[100 0 0]"},
+		{"scoop", []Pot{{100, []int{0, 1, 2}}}, []int{3, 2, 1}, []int{3, 2, 1}, "[100 0 0]"},
+		{"quartered", []Pot{{100, []int{0, 1, 2}}}, []int{3, 2, 1}, []int{2, 2, 1}, "[75 25 0]"},
+		{"low only in the side pot", []Pot{{90, []int{0, 1, 2}}, {40, []int{1, 2}}}, []int{1, 3, 2}, []int{5, 0, 1},
+			"[45 65 20]"},
+	}
+
+	for _, test := range tests {
+		winnings := AwardSplit(test.pots, test.high, test.low, cards, 0, LeftOfButton)
+		if fmt.Sprint(winnings) != test.expectedWinnings {
+			t.Errorf("%s: AwardSplit(%v, %v, %v) == %v but expected %s", test.name, test.pots, test.high, test.low, winnings, test.expectedWinnings)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package preflop // github.com/sildani/poker-hands-go/preflop
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/sildani/poker-hands-go/evaluator"
+	"github.com/sildani/poker-hands-go/isomorph"
+	"github.com/sildani/poker-hands-go/parser"
+	"io"
+)
+
+// Boards is how many boards can come once two hands are dealt: every five
+// of the other 48 cards.
+const Boards = 1712304
+
+const showdownsMagic = "PFSD"
+const showdownsVersion = 1
+
+//go:embed exact.dat
+var exactData []byte
+
+// Showdowns counts, for every class against every other heads up, the
+// showdowns it wins over every board and every pair of combos that can be
+// dealt together, a tie counting as half. The counts are doubled to keep
+// them whole.
+type Showdowns [Classes][Classes]uint32
+
+// Exact is the heads-up table worked out from the showdowns shipped with
+// the package, which cmd/preflop-table -exact enumerates. Unlike Generate
+// its equities are exact and count card removal: AA against AKs only meets
+// the combos of AKs that are left.
+func Exact() *Table {
+	showdowns, err := LoadShowdowns(bytes.NewReader(exactData))
+	if err != nil {
+		panic(fmt.Sprintf("exact.dat: %v", err))
+	}
+	return showdowns.Table()
+}
+
+// Pairs is how many ways class and other can be dealt to two players, with
+// no card in both.
+func Pairs(class int, other int) int {
+	pairs := 0
+	for _, hole := range Combos(class) {
+		for _, otherHole := range Combos(other) {
+			if hole[0] != otherHole[0] && hole[0] != otherHole[1] &&
+				hole[1] != otherHole[0] && hole[1] != otherHole[1] {
+				pairs++
+			}
+		}
+	}
+	return pairs
+}
+
+// EnumerateShowdowns plays out every board for every pair of combos. Boards
+// that only differ by suits play out the same way, so it plays one of each
+// and counts it once for every suit change that gives a different board.
+// It still takes minutes.
+func EnumerateShowdowns() *Showdowns {
+	cards := parser.Cards()
+	cardIndex := map[string]int{}
+	for i, card := range cards {
+		cardIndex[card] = i
+	}
+	masks := []uint64{}
+	classes := []int{}
+	holes := [][]string{}
+	for a := range cards {
+		for b := a + 1; b < len(cards); b++ {
+			hole := []string{cards[a], cards[b]}
+			masks = append(masks, 1<<uint(a)|1<<uint(b))
+			classes = append(classes, ClassOf(hole))
+			holes = append(holes, hole)
+		}
+	}
+
+	var counts [Classes][Classes]uint64
+	liveMasks := make([]uint64, 0, len(holes))
+	liveClasses := make([]int, 0, len(holes))
+	liveScores := make([]int, 0, len(holes))
+	indexer, _ := isomorph.NewIndexer([]int{5})
+	for i := 0; i < indexer.Size(); i++ {
+		rounds, _ := indexer.Unindex(i)
+		board := rounds[0]
+		boardMask := uint64(0)
+		for _, card := range board {
+			boardMask |= 1 << uint(cardIndex[card])
+		}
+		weight := uint64(orbit(board, cardIndex))
+
+		liveMasks, liveClasses, liveScores = liveMasks[:0], liveClasses[:0], liveScores[:0]
+		hand := append(append([]string{}, board...), "", "")
+		for combo, mask := range masks {
+			if mask&boardMask == 0 {
+				hand[5], hand[6] = holes[combo][0], holes[combo][1]
+				liveMasks = append(liveMasks, mask)
+				liveClasses = append(liveClasses, classes[combo])
+				liveScores = append(liveScores, evaluator.Score(hand))
+			}
+		}
+
+		for j := range liveMasks {
+			for k := j + 1; k < len(liveMasks); k++ {
+				if liveMasks[j]&liveMasks[k] != 0 {
+					continue
+				}
+				first, second := liveClasses[j], liveClasses[k]
+				if liveScores[j] > liveScores[k] {
+					counts[first][second] += 2 * weight
+				} else if liveScores[j] < liveScores[k] {
+					counts[second][first] += 2 * weight
+				} else {
+					counts[first][second] += weight
+					counts[second][first] += weight
+				}
+			}
+		}
+	}
+
+	showdowns := &Showdowns{}
+	for class := range counts {
+		for other := range counts[class] {
+			showdowns[class][other] = uint32(counts[class][other])
+		}
+	}
+	return showdowns
+}
+
+// orbit is how many boards differ from board only by suits.
+func orbit(board []string, cardIndex map[string]int) int {
+	boards := map[uint64]bool{}
+	for _, order := range permutations(suits) {
+		mask := uint64(0)
+		for _, card := range board {
+			for i, suit := range suits {
+				if card[1:] == suit {
+					mask |= 1 << uint(cardIndex[card[:1]+order[i]])
+				}
+			}
+		}
+		boards[mask] = true
+	}
+	return len(boards)
+}
+
+func permutations(items []string) [][]string {
+	if len(items) <= 1 {
+		return [][]string{append([]string{}, items...)}
+	}
+	all := [][]string{}
+	for i, item := range items {
+		rest := append(append([]string{}, items[:i]...), items[i+1:]...)
+		for _, permutation := range permutations(rest) {
+			all = append(all, append([]string{item}, permutation...))
+		}
+	}
+	return all
+}
+
+// Table works out the exact heads-up table: each class's equity against
+// each other, and against a random hand from the pairs it can meet.
+func (s *Showdowns) Table() *Table {
+	table := newTable(1)
+	for class := 0; class < Classes; class++ {
+		won, played := 0.0, 0.0
+		for other := 0; other < Classes; other++ {
+			pairs := Pairs(class, other)
+			table.vsClass[class][other] = float64(s[class][other]) / float64(2*pairs*Boards)
+			won += float64(s[class][other])
+			played += float64(2 * pairs * Boards)
+		}
+		table.vsRandom[class][0] = won / played
+	}
+	return table
+}
+
+// Write stores the showdowns as "PFSD", a version byte and the counts as
+// big-endian uint32s, class by class.
+func (s *Showdowns) Write(w io.Writer) error {
+	buffered := bufio.NewWriter(w)
+	buffered.WriteString(showdownsMagic)
+	buffered.WriteByte(showdownsVersion)
+	binary.Write(buffered, binary.BigEndian, s)
+	return buffered.Flush()
+}
+
+func LoadShowdowns(r io.Reader) (*Showdowns, error) {
+	header := make([]byte, len(showdownsMagic)+1)
+	if _, err := io.ReadFull(r, header); err != nil || string(header[:len(showdownsMagic)]) != showdownsMagic {
+		return nil, errors.New("Invalid showdowns: missing PFSD header")
+	}
+	if header[len(showdownsMagic)] != showdownsVersion {
+		return nil, fmt.Errorf("Invalid showdowns: unsupported version %d", header[len(showdownsMagic)])
+	}
+	showdowns := &Showdowns{}
+	if err := binary.Read(bufio.NewReader(r), binary.BigEndian, showdowns); err != nil {
+		return nil, errors.New("Invalid showdowns: truncated")
+	}
+	return showdowns, nil
+}
@@ -0,0 +1,106 @@
+package preflop // github.com/sildani/poker-hands-go/preflop
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestPairs(t *testing.T) {
+	tests := []struct {
+		class         string
+		other         string
+		expectedPairs int
+	}{
+		{"AA", "KK", 36},
+		{"AA", "AA", 6},
+		{"AA", "AKs", 12},
+		{"AKs", "AA", 12},
+		{"AKo", "AKs", 24},
+		{"72o", "T9s", 48},
+	}
+
+	for _, test := range tests {
+		class, _ := ParseClass(test.class)
+		other, _ := ParseClass(test.other)
+		if pairs := Pairs(class, other); pairs != test.expectedPairs {
+			t.Errorf("Pairs(%s, %s) == %d but expected %d", test.class, test.other, pairs, test.expectedPairs)
+		}
+	}
+
+	for class := 0; class < Classes; class++ {
+		total := 0
+		for other := 0; other < Classes; other++ {
+			total += Pairs(class, other)
+		}
+		if total != len(Combos(class))*1225 {
+			t.Errorf("Pairs(%s, ...) add up to %d but expected every combo to meet 1225 others", ClassName(class), total)
+		}
+	}
+}
+
+func TestExact(t *testing.T) {
+	table := Exact()
+	aces, _ := ParseClass("AA")
+	kings, _ := ParseClass("KK")
+	aceKing, _ := ParseClass("AKs")
+	sevenDeuce, _ := ParseClass("72o")
+	threeDeuce, _ := ParseClass("32o")
+
+	tests := []struct {
+		name           string
+		equity         float64
+		expectedEquity float64
+	}{
+		{"AA against a random hand", table.VsRandom(aces, 1), 0.8520},
+		{"KK against a random hand", table.VsRandom(kings, 1), 0.8240},
+		{"72o against a random hand", table.VsRandom(sevenDeuce, 1), 0.3458},
+		{"32o against a random hand", table.VsRandom(threeDeuce, 1), 0.3230},
+		{"AA against KK", table.VsClass(aces, kings), 0.8195},
+		{"AA against AKs", table.VsClass(aces, aceKing), 0.8786},
+	}
+	for _, test := range tests {
+		if math.Abs(test.equity-test.expectedEquity) > 0.0001 {
+			t.Errorf("%s == %.5f but expected %.4f", test.name, test.equity, test.expectedEquity)
+		}
+	}
+
+	for class := 0; class < Classes; class++ {
+		for other := 0; other < Classes; other++ {
+			if sum := table.VsClass(class, other) + table.VsClass(other, class); math.Abs(sum-1) > 1e-12 {
+				t.Errorf("VsClass(%s, %s) and back add up to %f but expected 1", ClassName(class), ClassName(other), sum)
+			}
+		}
+	}
+}
+
+func TestShowdownsWriteAndLoad(t *testing.T) {
+	showdowns := &Showdowns{}
+	showdowns[0][1], showdowns[168][167] = 7, 1<<31
+
+	var buffer bytes.Buffer
+	if err := showdowns.Write(&buffer); err != nil {
+		t.Fatalf("Write err == %q but expected nil", err)
+	}
+	if buffer.Len() != 5+4*Classes*Classes {
+		t.Errorf("Write wrote %d bytes but expected %d", buffer.Len(), 5+4*Classes*Classes)
+	}
+	loaded, err := LoadShowdowns(&buffer)
+	if err != nil || *loaded != *showdowns {
+		t.Errorf("LoadShowdowns err == %v or the counts changed on the way through", err)
+	}
+
+	tests := []struct {
+		data        []byte
+		expectedErr string
+	}{
+		{[]byte("PFEQ\x01"), "Invalid showdowns: missing PFSD header"},
+		{[]byte("PFSD\x02"), "Invalid showdowns: unsupported version 2"},
+		{[]byte("PFSD\x01\x00\x00"), "Invalid showdowns: truncated"},
+	}
+	for _, test := range tests {
+		if _, err := LoadShowdowns(bytes.NewReader(test.data)); err == nil || err.Error() != test.expectedErr {
+			t.Errorf("LoadShowdowns(%q) err == %v but expected %q", test.data, err, test.expectedErr)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package preflop // github.com/sildani/poker-hands-go/preflop
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/evaluator"
+	"github.com/sildani/poker-hands-go/parser"
+	"io"
+	"math"
+)
+
+// Classes are laid out as a 13x13 chart with aces in the first row and
+// column: pairs on the diagonal, suited hands above it and offsuit hands
+// below it.
+const Classes = 169
+
+const magic = "PFEQ"
+const version = 1
+
+var ranks = []string{"A", "K", "Q", "J", "T", "9", "8", "7", "6", "5", "4", "3", "2"}
+var suits = []string{"H", "S", "C", "D"}
+
+type Table struct {
+	opponents int
+	vsRandom  [][]float64
+	vsClass   [][]float64
+}
+
+func ClassOf(hole []string) int {
+	first := rankIndex(hole[0][:1])
+	second := rankIndex(hole[1][:1])
+	if first > second {
+		first, second = second, first
+	}
+	if hole[0][1:] == hole[1][1:] {
+		return first*13 + second
+	}
+	return second*13 + first
+}
+
+func ClassName(class int) string {
+	row := class / 13
+	column := class % 13
+	if row == column {
+		return ranks[row] + ranks[column]
+	} else if row < column {
+		return ranks[row] + ranks[column] + "s"
+	}
+	return ranks[column] + ranks[row] + "o"
+}
+
+func ParseClass(name string) (int, error) {
+	for class := 0; class < Classes; class++ {
+		if ClassName(class) == name {
+			return class, nil
+		}
+	}
+	return 0, fmt.Errorf("Invalid class: %q must look like AA, AKs or AKo", name)
+}
+
+// Combos returns the hole cards making up a class: six for a pair, four
+// when suited and twelve when offsuit.
+func Combos(class int) [][]string {
+	row := class / 13
+	column := class % 13
+	combos := [][]string{}
+	for i, firstSuit := range suits {
+		for j, secondSuit := range suits {
+			if (row == column && j > i) ||
+				(row < column && i == j) ||
+				(row > column && i != j) {
+				combos = append(combos, []string{ranks[row] + firstSuit, ranks[column] + secondSuit})
+			}
+		}
+	}
+	return combos
+}
+
+// EquityVsRandom estimates the share of the pot class wins against
+// opponents random hands by playing samples random boards.
+func EquityVsRandom(class int, opponents int, samples int, rng deck.RNG) float64 {
+	combos := Combos(class)
+	decks := []*deck.Deck{}
+	for _, hole := range combos {
+		decks = append(decks, without(hole, rng))
+	}
+	equity := 0.0
+	for i := 0; i < samples; i++ {
+		combo := rng.Intn(len(combos))
+		hole := combos[combo]
+		cards, _ := decks[combo].Sample(2*opponents + 5)
+		hands := [][]string{hole}
+		for j := 0; j < opponents; j++ {
+			hands = append(hands, cards[2*j:2*j+2])
+		}
+		equity += share(hands, cards[2*opponents:])
+	}
+	return equity / float64(samples)
+}
+
+// EquityVsClass estimates the share of the pot class wins heads up against
+// other by playing samples random boards.
+func EquityVsClass(class int, other int, samples int, rng deck.RNG) float64 {
+	combos := Combos(class)
+	otherCombos := Combos(other)
+	decks := map[[2]int]*deck.Deck{}
+	equity := 0.0
+	for i := 0; i < samples; i++ {
+		combo := rng.Intn(len(combos))
+		otherCombo := rng.Intn(len(otherCombos))
+		hole, otherHole := combos[combo], otherCombos[otherCombo]
+		for hole[0] == otherHole[0] || hole[0] == otherHole[1] ||
+			hole[1] == otherHole[0] || hole[1] == otherHole[1] {
+			combo = rng.Intn(len(combos))
+			otherCombo = rng.Intn(len(otherCombos))
+			hole, otherHole = combos[combo], otherCombos[otherCombo]
+		}
+		d := decks[[2]int{combo, otherCombo}]
+		if d == nil {
+			d = without(append(append([]string{}, hole...), otherHole...), rng)
+			decks[[2]int{combo, otherCombo}] = d
+		}
+		boardCards, _ := d.Sample(5)
+		equity += share([][]string{hole, otherHole}, boardCards)
+	}
+	return equity / float64(samples)
+}
+
+// Generate builds a table of every class against one to opponents random
+// hands and heads up against every other class.
+func Generate(opponents int, samples int, rng deck.RNG) *Table {
+	table := newTable(opponents)
+	for class := 0; class < Classes; class++ {
+		for n := 1; n <= opponents; n++ {
+			table.vsRandom[class][n-1] = EquityVsRandom(class, n, samples, rng)
+		}
+		table.vsClass[class][class] = 0.5
+		for other := class + 1; other < Classes; other++ {
+			equity := EquityVsClass(class, other, samples, rng)
+			table.vsClass[class][other] = equity
+			table.vsClass[other][class] = 1 - equity
+		}
+	}
+	return table
+}
+
+func (t *Table) Opponents() int {
+	return t.opponents
+}
+
+func (t *Table) VsRandom(class int, opponents int) float64 {
+	return t.vsRandom[class][opponents-1]
+}
+
+func (t *Table) VsClass(class int, other int) float64 {
+	return t.vsClass[class][other]
+}
+
+// Write stores the table as "PFEQ", a version byte and an opponents byte,
+// followed by every equity as a big endian uint16 fraction of 65535.
+func (t *Table) Write(w io.Writer) error {
+	buffered := bufio.NewWriter(w)
+	buffered.WriteString(magic)
+	buffered.WriteByte(version)
+	buffered.WriteByte(byte(t.opponents))
+	for class := 0; class < Classes; class++ {
+		for _, equity := range t.vsRandom[class] {
+			binary.Write(buffered, binary.BigEndian, uint16(math.Round(equity*math.MaxUint16)))
+		}
+	}
+	for class := 0; class < Classes; class++ {
+		for _, equity := range t.vsClass[class] {
+			binary.Write(buffered, binary.BigEndian, uint16(math.Round(equity*math.MaxUint16)))
+		}
+	}
+	return buffered.Flush()
+}
+
+func Load(r io.Reader) (*Table, error) {
+	header := make([]byte, len(magic)+2)
+	if _, err := io.ReadFull(r, header); err != nil || string(header[:len(magic)]) != magic {
+		return nil, errors.New("Invalid table: missing PFEQ header")
+	}
+	if header[len(magic)] != version {
+		return nil, fmt.Errorf("Invalid table: unsupported version %d", header[len(magic)])
+	}
+
+	table := newTable(int(header[len(magic)+1]))
+	equities := make([]uint16, Classes*table.opponents+Classes*Classes)
+	if err := binary.Read(bufio.NewReader(r), binary.BigEndian, equities); err != nil {
+		return nil, errors.New("Invalid table: truncated")
+	}
+	for class := 0; class < Classes; class++ {
+		for n := 0; n < table.opponents; n++ {
+			table.vsRandom[class][n] = float64(equities[0]) / math.MaxUint16
+			equities = equities[1:]
+		}
+	}
+	for class := 0; class < Classes; class++ {
+		for other := 0; other < Classes; other++ {
+			table.vsClass[class][other] = float64(equities[0]) / math.MaxUint16
+			equities = equities[1:]
+		}
+	}
+	return table, nil
+}
+
+func newTable(opponents int) *Table {
+	table := &Table{opponents: opponents}
+	for class := 0; class < Classes; class++ {
+		table.vsRandom = append(table.vsRandom, make([]float64, opponents))
+		table.vsClass = append(table.vsClass, make([]float64, Classes))
+	}
+	return table
+}
+
+// share returns the part of the pot the first hand wins on board.
+func share(hands [][]string, boardCards []string) float64 {
+	best := 0
+	winners := 0
+	heroWins := false
+	for i, hand := range hands {
+		score := evaluator.Score(append(append([]string{}, boardCards...), hand...))
+		if score > best {
+			best = score
+			winners = 0
+			heroWins = false
+		}
+		if score == best {
+			winners++
+			heroWins = heroWins || i == 0
+		}
+	}
+	if !heroWins {
+		return 0
+	}
+	return 1 / float64(winners)
+}
+
+// without returns a deck of the cards that are not known, to sample from.
+func without(known []string, rng deck.RNG) *deck.Deck {
+	d := deck.New(rng)
+	d.Remove(known...)
+	return d
+}
+
+func rankIndex(rank string) int {
+	value, _ := parser.ParseCardValue(rank)
+	return 14 - value
+}
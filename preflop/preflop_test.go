@@ -0,0 +1,143 @@
+package preflop // github.com/sildani/poker-hands-go/preflop
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestClasses(t *testing.T) {
+	tests := []struct {
+		hole           []string
+		expectedName   string
+		expectedCombos int
+	}{
+		{[]string{"AH", "AS"}, "AA", 6},
+		{[]string{"KD", "AD"}, "AKs", 4},
+		{[]string{"AD", "KC"}, "AKo", 12},
+		{[]string{"2C", "7D"}, "72o", 12},
+		{[]string{"2C", "2D"}, "22", 6},
+		{[]string{"TS", "9S"}, "T9s", 4},
+	}
+
+	for _, test := range tests {
+		class := ClassOf(test.hole)
+		if ClassName(class) != test.expectedName {
+			t.Errorf("ClassName(ClassOf(%v)) == %q but expected %q", test.hole, ClassName(class), test.expectedName)
+		}
+		parsed, err := ParseClass(test.expectedName)
+		if err != nil || parsed != class {
+			t.Errorf("ParseClass(%q) == %d, %v but expected %d, nil", test.expectedName, parsed, err, class)
+		}
+		combos := Combos(class)
+		if len(combos) != test.expectedCombos {
+			t.Errorf("len(Combos(%q)) == %d but expected %d", test.expectedName, len(combos), test.expectedCombos)
+		}
+		for _, combo := range combos {
+			if ClassOf(combo) != class {
+				t.Errorf("Combos(%q) contains %v of class %q", test.expectedName, combo, ClassName(ClassOf(combo)))
+			}
+		}
+	}
+}
+
+func TestEveryHoldingHasOneClass(t *testing.T) {
+	seen := make(map[string]bool)
+	total := 0
+	for class := 0; class < Classes; class++ {
+		if seen[ClassName(class)] {
+			t.Errorf("ClassName(%d) == %q is not unique", class, ClassName(class))
+		}
+		seen[ClassName(class)] = true
+		total += len(Combos(class))
+	}
+	if total != 1326 {
+		t.Errorf("Combos of every class add up to %d but expected 1326", total)
+	}
+}
+
+func TestParseClassInvalid(t *testing.T) {
+	for _, name := range []string{"", "AAs", "KAs", "AK", "A1o"} {
+		if _, err := ParseClass(name); err == nil {
+			t.Errorf("ParseClass(%q) err == nil but expected an error", name)
+		}
+	}
+}
+
+func TestEquities(t *testing.T) {
+	aces, _ := ParseClass("AA")
+	kings, _ := ParseClass("KK")
+	sevenDeuce, _ := ParseClass("72o")
+	rng := rand.New(rand.NewSource(1))
+
+	tests := []struct {
+		name           string
+		equity         float64
+		expectedEquity float64
+	}{
+		{"AA against a random hand", EquityVsRandom(aces, 1, 4000, rng), 0.852},
+		{"AA against four random hands", EquityVsRandom(aces, 4, 4000, rng), 0.557},
+		{"72o against a random hand", EquityVsRandom(sevenDeuce, 1, 4000, rng), 0.346},
+		{"AA against KK", EquityVsClass(aces, kings, 4000, rng), 0.820},
+	}
+
+	for _, test := range tests {
+		if math.Abs(test.equity-test.expectedEquity) > 0.025 {
+			t.Errorf("%s == %.3f but expected %.3f", test.name, test.equity, test.expectedEquity)
+		}
+	}
+}
+
+func TestWriteAndLoad(t *testing.T) {
+	table := Generate(2, 1, rand.New(rand.NewSource(1)))
+
+	var buffer bytes.Buffer
+	if err := table.Write(&buffer); err != nil {
+		t.Fatalf("Write err == %q but expected nil", err)
+	}
+	if buffer.Len() != 6+2*(Classes*2+Classes*Classes) {
+		t.Errorf("Write wrote %d bytes but expected %d", buffer.Len(), 6+2*(Classes*2+Classes*Classes))
+	}
+
+	loaded, err := Load(&buffer)
+	if err != nil {
+		t.Fatalf("Load err == %q but expected nil", err)
+	}
+	if loaded.Opponents() != 2 {
+		t.Errorf("Load(...).Opponents() == %d but expected 2", loaded.Opponents())
+	}
+	for class := 0; class < Classes; class++ {
+		for n := 1; n <= 2; n++ {
+			if math.Abs(loaded.VsRandom(class, n)-table.VsRandom(class, n)) > 1.0/65535 {
+				t.Errorf("VsRandom(%q, %d) == %f after loading but expected %f",
+					ClassName(class), n, loaded.VsRandom(class, n), table.VsRandom(class, n))
+			}
+		}
+		for other := 0; other < Classes; other++ {
+			if math.Abs(loaded.VsClass(class, other)-table.VsClass(class, other)) > 1.0/65535 {
+				t.Errorf("VsClass(%q, %q) == %f after loading but expected %f", ClassName(class), ClassName(other),
+					loaded.VsClass(class, other), table.VsClass(class, other))
+			}
+		}
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		data        []byte
+		expectedErr string
+	}{
+		{[]byte{}, "Invalid table: missing PFEQ header"},
+		{[]byte("NOPE\x01\x01"), "Invalid table: missing PFEQ header"},
+		{[]byte("PFEQ\x02\x01"), "Invalid table: unsupported version 2"},
+		{[]byte("PFEQ\x01\x01\x00\x01"), "Invalid table: truncated"},
+	}
+
+	for _, test := range tests {
+		_, err := Load(bytes.NewReader(test.data))
+		if err == nil || err.Error() != test.expectedErr {
+			t.Errorf("Load(%q) err == %v but expected %q", test.data, err, test.expectedErr)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package pushfold // github.com/sildani/poker-hands-go/pushfold
+
+import (
+	"fmt"
+	"github.com/sildani/poker-hands-go/preflop"
+	"strings"
+)
+
+// Equities gives the all-in equity of one starting hand class against
+// another heads up, as a preflop.Table does. preflop.Exact has the exact
+// ones.
+type Equities interface {
+	VsClass(class int, other int) float64
+}
+
+// Result is a push/fold equilibrium. Push and Call are the chance the
+// small blind shoves and the big blind calls with each class. Value is
+// what the small blind wins a hand on average and Exploitability what
+// best responses to both ranges would win on top, both in big blinds;
+// Exploitability is zero at an exact equilibrium.
+type Result struct {
+	Push           []float64
+	Call           []float64
+	Value          float64
+	Exploitability float64
+}
+
+// game holds the payoffs in big blinds for an effective stack, counting
+// from before the blinds go in. pairs weighs the small blind holding one
+// class and the big blind another by the ways they can be dealt together.
+type game struct {
+	equities   Equities
+	pairs      [][]float64
+	stack      float64
+	smallBlind float64
+}
+
+// Solve finds the small blind's shoving range and the big blind's calling
+// range heads up, with stack the effective stack in big blinds and the
+// small blind half a big blind unless set, by fictitious play: each side
+// plays a best response to the other's average so far, and the averages
+// converge on the equilibrium. Hands are weighted by the combos that can
+// be dealt together, so card removal counts; with preflop.Exact for the
+// equities the result is the exact push/fold equilibrium.
+func Solve(equities Equities, stack float64, smallBlind float64, iterations int) Result {
+	if smallBlind == 0 {
+		smallBlind = 0.5
+	}
+	g := game{equities: equities, stack: stack, smallBlind: smallBlind}
+	for class := 0; class < preflop.Classes; class++ {
+		g.pairs = append(g.pairs, make([]float64, preflop.Classes))
+		for other := 0; other < preflop.Classes; other++ {
+			g.pairs[class][other] = float64(preflop.Pairs(class, other))
+		}
+	}
+
+	push := make([]float64, preflop.Classes)
+	call := make([]float64, preflop.Classes)
+	for i := 1; i <= iterations; i++ {
+		pushResponse, _ := g.bestPush(call)
+		callResponse, _ := g.bestCall(push)
+		for class := range push {
+			push[class] += (pushResponse[class] - push[class]) / float64(i)
+			call[class] += (callResponse[class] - call[class]) / float64(i)
+		}
+	}
+
+	value := g.value(push, call)
+	_, pushBest := g.bestPush(call)
+	_, callBest := g.bestCall(push)
+	return Result{
+		Push:           push,
+		Call:           call,
+		Value:          value,
+		Exploitability: (pushBest - value) + (value - callBest),
+	}
+}
+
+// pushValue is what the small blind wins shoving class against the call
+// range, averaged over the hands the big blind could hold, and how many
+// pairs of hands that is over.
+func (g game) pushValue(class int, call []float64) (float64, float64) {
+	value, total := 0.0, 0.0
+	for other := range call {
+		showdown := g.stack * (2*g.equities.VsClass(class, other) - 1)
+		value += g.pairs[class][other] * (call[other]*showdown + (1-call[other])*1)
+		total += g.pairs[class][other]
+	}
+	return value / total, total
+}
+
+// bestPush is the small blind's best response to a call range and what it
+// wins.
+func (g game) bestPush(call []float64) ([]float64, float64) {
+	response := make([]float64, preflop.Classes)
+	value, total := 0.0, 0.0
+	for class := range response {
+		best := -g.smallBlind
+		push, pairs := g.pushValue(class, call)
+		if push >= best {
+			response[class], best = 1, push
+		}
+		value += pairs * best
+		total += pairs
+	}
+	return response, value / total
+}
+
+// bestCall is the big blind's best response to a shoving range and what
+// the small blind wins against it.
+func (g game) bestCall(push []float64) ([]float64, float64) {
+	response := make([]float64, preflop.Classes)
+	value, total := 0.0, 0.0
+	for class := range response {
+		folded, folds, calls := 0.0, 0.0, 0.0
+		for other := range push {
+			pairs := g.pairs[other][class]
+			folded -= pairs * (1 - push[other]) * g.smallBlind
+			folds += pairs * push[other]
+			calls += pairs * push[other] * g.stack * (2*g.equities.VsClass(other, class) - 1)
+			total += pairs
+		}
+		best := folds
+		if calls < folds {
+			response[class], best = 1, calls
+		}
+		value += folded + best
+	}
+	return response, value / total
+}
+
+// value is what the small blind wins a hand on average with both ranges.
+func (g game) value(push []float64, call []float64) float64 {
+	value, total := 0.0, 0.0
+	for class := range push {
+		pushed, pairs := g.pushValue(class, call)
+		value += pairs * (push[class]*pushed - (1-push[class])*g.smallBlind)
+		total += pairs
+	}
+	return value / total
+}
+
+// Chart lays a strategy out as the 13x13 chart of preflop classes, aces
+// first, each class with the percentage of the time it is played.
+func Chart(strategy []float64) string {
+	var chart strings.Builder
+	for row := 0; row < 13; row++ {
+		cells := []string{}
+		for column := 0; column < 13; column++ {
+			class := row*13 + column
+			cells = append(cells, fmt.Sprintf("%-3s %3.0f", preflop.ClassName(class), 100*strategy[class]))
+		}
+		chart.WriteString(strings.Join(cells, "  ") + "\n")
+	}
+	return chart.String()
+}
@@ -0,0 +1,106 @@
+package pushfold // github.com/sildani/poker-hands-go/pushfold
+
+import (
+	"github.com/sildani/poker-hands-go/preflop"
+	"strings"
+	"testing"
+)
+
+// ranked makes lower classes in the chart stronger, with equities that
+// add up to one each way.
+type ranked struct{}
+
+func (ranked) VsClass(class int, other int) float64 {
+	return 0.5 + 0.3*float64(other-class)/preflop.Classes
+}
+
+func pushed(strategy []float64) float64 {
+	total := 0.0
+	for class, chance := range strategy {
+		total += chance * float64(len(preflop.Combos(class)))
+	}
+	return total / 1326
+}
+
+func TestSolve(t *testing.T) {
+	short := Solve(ranked{}, 2, 0, 2000)
+	deep := Solve(ranked{}, 20, 0, 2000)
+
+	if short.Exploitability > 0.01 || deep.Exploitability > 0.01 {
+		t.Errorf("Exploitability == %.4f and %.4f but expected under 0.01 big blinds", short.Exploitability, deep.Exploitability)
+	}
+	if pushed(short.Push) <= pushed(deep.Push) || pushed(short.Call) <= pushed(deep.Call) {
+		t.Errorf("2bb pushes %.2f and calls %.2f, 20bb pushes %.2f and calls %.2f, but expected wider ranges shorter",
+			pushed(short.Push), pushed(short.Call), pushed(deep.Push), pushed(deep.Call))
+	}
+	if short.Push[0] < 0.99 || deep.Call[0] < 0.99 || deep.Push[preflop.Classes-1] > 0.01 {
+		t.Errorf("Push[0] == %.2f, Call[0] == %.2f and Push[last] == %.2f but expected the best class in and the worst out",
+			short.Push[0], deep.Call[0], deep.Push[preflop.Classes-1])
+	}
+}
+
+func TestSolveWithTable(t *testing.T) {
+	table := preflop.Exact()
+	result := Solve(table, 10, 0, 500)
+
+	aces, _ := preflop.ParseClass("AA")
+	sevenDeuce, _ := preflop.ParseClass("72o")
+	if result.Push[aces] < 0.99 || result.Call[aces] < 0.99 || result.Call[sevenDeuce] > 0.01 {
+		t.Errorf("AA pushes %.2f and calls %.2f, 72o calls %.2f, but expected AA always in and 72o never called with",
+			result.Push[aces], result.Call[aces], result.Call[sevenDeuce])
+	}
+	if result.Exploitability > 0.01 {
+		t.Errorf("Exploitability == %.4f but expected under 0.01 big blinds", result.Exploitability)
+	}
+}
+
+// TestSolveNashCharts checks the solution against the published heads-up
+// push/fold Nash charts, which are worked out with card removal.
+func TestSolveNashCharts(t *testing.T) {
+	table := preflop.Exact()
+	tests := []struct {
+		stack        float64
+		expectedPush float64
+		expectedCall float64
+		pushed       []string
+		folded       []string
+		called       []string
+		notCalled    []string
+	}{
+		{10, 0.584, 0.374, []string{"22", "A2o", "K2s", "K2o", "Q8o", "J7s"}, []string{"72o", "32o", "T2o"},
+			[]string{"22", "A2o", "K7o", "Q9s"}, []string{"72o", "J9o", "T8s"}},
+		{20, 0.402, 0.217, []string{"22", "A2o", "K9o", "Q9s"}, []string{"72o", "K5o", "J5o"},
+			[]string{"33", "A7o", "A2s", "KJs"}, []string{"K9o", "QJo", "T9s"}},
+	}
+
+	for _, test := range tests {
+		result := Solve(table, test.stack, 0, 2000)
+		if push := pushed(result.Push); push < test.expectedPush-0.01 || push > test.expectedPush+0.01 {
+			t.Errorf("%.0fbb pushes %.3f of hands but expected %.3f", test.stack, push, test.expectedPush)
+		}
+		if call := pushed(result.Call); call < test.expectedCall-0.01 || call > test.expectedCall+0.01 {
+			t.Errorf("%.0fbb calls %.3f of hands but expected %.3f", test.stack, call, test.expectedCall)
+		}
+		check := func(strategy []float64, names []string, in bool, what string) {
+			for _, name := range names {
+				class, _ := preflop.ParseClass(name)
+				if in && strategy[class] < 0.99 || !in && strategy[class] > 0.01 {
+					t.Errorf("%.0fbb %s %s %.2f of the time but expected %v", test.stack, what, name, strategy[class], in)
+				}
+			}
+		}
+		check(result.Push, test.pushed, true, "pushes")
+		check(result.Push, test.folded, false, "pushes")
+		check(result.Call, test.called, true, "calls with")
+		check(result.Call, test.notCalled, false, "calls with")
+	}
+}
+
+func TestChart(t *testing.T) {
+	strategy := make([]float64, preflop.Classes)
+	strategy[0], strategy[1], strategy[13] = 1, 0.5, 0.25
+	lines := strings.Split(Chart(strategy), "\n")
+	if len(lines) != 14 || !strings.HasPrefix(lines[0], "AA  100  AKs  50  AQs   0") || !strings.HasPrefix(lines[1], "AKo  25  KK    0") {
+		t.Errorf("Chart ==\n%s\nbut expected AA, AKs and AKo played", Chart(strategy))
+	}
+}
@@ -0,0 +1,260 @@
+package remote // github.com/sildani/poker-hands-go/remote
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/sildani/poker-hands-go/holdem"
+	"io"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bot is a player in another process, spoken to in lines of text modelled
+// on the ACPC match state protocol. Whenever the bot is to act it is sent
+//
+//	MATCHSTATE:<seat>:<hand>:<betting>:<cards>
+//
+// and answers with the same line followed by :f to fold, :c to check or
+// call, or :r<N> to bet or raise so that it has put N into the pot this
+// hand. <betting> is the actions so far, f, c and r<N> with streets split
+// by /, and <cards> is every seat's hole cards split by |, only the bot's
+// own showing, then each street of the board after a /. Cards are written
+// rank then lower case suit, as in Ah. Lines the bot sends that do not
+// answer the current state, such as an ACPC VERSION line, are ignored.
+//
+// Hands are numbered from 1 as StartHand is called for each, so a number
+// is never used twice. EndHand sends the final state of the hand, with
+// the hole cards of a showdown, which the bot does not answer.
+//
+// A bot that does not answer within Timeout, sends something that is not
+// a legal action or goes away folds instead; Faults counts the times and
+// Err is the last reason.
+type Bot struct {
+	Name    string
+	Timeout time.Duration
+	Faults  int
+	Err     error
+
+	lines  chan string
+	done   chan struct{}
+	writer io.Writer
+	closer io.Closer
+	hand   int
+}
+
+// New speaks to a bot reading its answers from r and writing states to w.
+// Close closes closer. A zero timeout waits for ever.
+func New(name string, r io.Reader, w io.Writer, closer io.Closer, timeout time.Duration) *Bot {
+	b := &Bot{Name: name, Timeout: timeout, lines: make(chan string), done: make(chan struct{}), writer: w, closer: closer}
+	go func() {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			select {
+			case b.lines <- strings.TrimRight(scanner.Text(), "\r"):
+			case <-b.done:
+				return
+			}
+		}
+		close(b.lines)
+	}()
+	return b
+}
+
+// process is a running bot program, killed when the bot is closed.
+type process struct {
+	cmd   *exec.Cmd
+	stdin io.Closer
+}
+
+func (p process) Close() error {
+	p.stdin.Close()
+	p.cmd.Process.Kill()
+	p.cmd.Wait()
+	return nil
+}
+
+// Command starts a bot program and speaks to it over its standard input
+// and output.
+func Command(name string, timeout time.Duration, program string, args ...string) (*Bot, error) {
+	cmd := exec.Command(program, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("Invalid bot: %s %v", name, err)
+	}
+	return New(name, stdout, stdin, process{cmd: cmd, stdin: stdin}, timeout), nil
+}
+
+// Accept waits for a bot to connect to listener, and speaks to it over the
+// connection.
+func Accept(name string, timeout time.Duration, listener net.Listener) (*Bot, error) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return New(name, conn, conn, conn, timeout), nil
+}
+
+func (b *Bot) Close() error {
+	close(b.done)
+	return b.closer.Close()
+}
+
+// Act sends the bot the state and reads back its action.
+func (b *Bot) Act(view holdem.View) holdem.Action {
+	action, err := b.ask(view)
+	if err != nil {
+		b.Faults++
+		b.Err = err
+		return holdem.Action{Type: holdem.Fold}
+	}
+	return action
+}
+
+// StartHand numbers the next hand.
+func (b *Bot) StartHand(view holdem.View) {
+	b.hand++
+}
+
+// EndHand sends the bot the final state of the hand.
+func (b *Bot) EndHand(view holdem.View) {
+	if err := b.send(State(view, b.hand)); err != nil {
+		b.Err = err
+	}
+}
+
+func (b *Bot) send(state string) error {
+	if _, err := fmt.Fprintf(b.writer, "%s\r\n", state); err != nil {
+		return fmt.Errorf("Invalid bot: %s has gone (%v)", b.Name, err)
+	}
+	return nil
+}
+
+func (b *Bot) ask(view holdem.View) (holdem.Action, error) {
+	state := State(view, b.hand)
+	if err := b.send(state); err != nil {
+		return holdem.Action{}, err
+	}
+
+	var timeout <-chan time.Time
+	if b.Timeout > 0 {
+		timeout = time.After(b.Timeout)
+	}
+	for {
+		select {
+		case line, ok := <-b.lines:
+			if !ok {
+				return holdem.Action{}, fmt.Errorf("Invalid bot: %s has gone", b.Name)
+			}
+			if reply, found := strings.CutPrefix(line, state+":"); found {
+				action, err := Parse(view, reply)
+				if err != nil {
+					return holdem.Action{}, fmt.Errorf("Invalid bot: %s %v", b.Name, err)
+				}
+				return action, nil
+			}
+		case <-timeout:
+			return holdem.Action{}, fmt.Errorf("Invalid bot: %s took longer than %v", b.Name, b.Timeout)
+		}
+	}
+}
+
+// State writes the match state line for a view of hand number hand. There
+// is a betting street for each street of the board dealt, so a hand over
+// before the flop has one.
+func State(view holdem.View, hand int) string {
+	streets := make([]string, 1)
+	for _, end := range []int{3, 4, 5} {
+		if len(view.Board) >= end {
+			streets = append(streets, "")
+		}
+	}
+	previous := make([]int, len(view.Players))
+	bets := make([]int, len(view.Players))
+	street := holdem.Preflop
+	for _, event := range view.Events {
+		for ; street < event.Street; street++ {
+			for seat := range bets {
+				previous[seat] += bets[seat]
+				bets[seat] = 0
+			}
+		}
+		switch event.Type {
+		case holdem.Ante:
+			previous[event.Seat] += event.Amount
+		case holdem.SmallBlind, holdem.BigBlind:
+			bets[event.Seat] += event.Amount
+		case holdem.Fold:
+			streets[event.Street] += "f"
+		case holdem.Check:
+			streets[event.Street] += "c"
+		case holdem.Call:
+			bets[event.Seat] += event.Amount
+			streets[event.Street] += "c"
+		case holdem.Bet, holdem.Raise:
+			bets[event.Seat] = event.Amount
+			streets[event.Street] += "r" + strconv.Itoa(previous[event.Seat]+event.Amount)
+		}
+	}
+
+	holes := make([]string, len(view.Players))
+	for seat, p := range view.Players {
+		holes[seat] = cards(p.Hole)
+	}
+	board := strings.Join(holes, "|")
+	start := 0
+	for _, end := range []int{3, 4, 5} {
+		if len(view.Board) >= end {
+			board += "/" + cards(view.Board[start:end])
+		}
+		start = end
+	}
+	return fmt.Sprintf("MATCHSTATE:%d:%d:%s:%s", view.Seat, hand, strings.Join(streets, "/"), board)
+}
+
+func cards(cards []string) string {
+	written := ""
+	for _, card := range cards {
+		written += card[:1] + strings.ToLower(card[1:])
+	}
+	return written
+}
+
+// Parse reads a bot's answer, f, c or r<N>, as an action for the view,
+// checking it is legal.
+func Parse(view holdem.View, reply string) (holdem.Action, error) {
+	switch {
+	case reply == "f":
+		return holdem.Action{Type: holdem.Fold}, nil
+	case reply == "c" && view.Options.Check:
+		return holdem.Action{Type: holdem.Check}, nil
+	case reply == "c":
+		return holdem.Action{Type: holdem.Call}, nil
+	case strings.HasPrefix(reply, "r"):
+		total, err := strconv.Atoi(reply[1:])
+		if err != nil {
+			break
+		}
+		p := view.Players[view.Seat]
+		amount := total - (p.Total - p.Bet)
+		if view.Options.MinRaise == 0 || amount < view.Options.MinRaise || amount > view.Options.MaxRaise {
+			return holdem.Action{}, fmt.Errorf("Invalid action: r%d must put in from %d to %d",
+				total, view.Options.MinRaise+p.Total-p.Bet, view.Options.MaxRaise+p.Total-p.Bet)
+		}
+		if view.CurrentBet == 0 {
+			return holdem.Action{Type: holdem.Bet, Amount: amount}, nil
+		}
+		return holdem.Action{Type: holdem.Raise, Amount: amount}, nil
+	}
+	return holdem.Action{}, errors.New("Invalid action: must be f, c or r<N>, not " + strconv.Quote(reply))
+}
@@ -0,0 +1,228 @@
+package remote // github.com/sildani/poker-hands-go/remote
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/holdem"
+	"github.com/sildani/poker-hands-go/sim"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// caller checks or calls every time.
+type caller struct{}
+
+func (caller) Act(view holdem.View) holdem.Action {
+	if view.Options.Check {
+		return holdem.Action{Type: holdem.Check}
+	}
+	return holdem.Action{Type: holdem.Call}
+}
+
+// newHand deals three players 100 each with the button on seat 0, so seat
+// 1 holds AH KH and the flop is QH JH TH.
+func newHand(t *testing.T) *holdem.Hand {
+	d, err := deck.NewStacked([]string{"AH", "2C", "3C", "KH", "4C", "5C", "2D", "QH", "JH", "TH", "3D", "9S", "4D", "8S"})
+	if err != nil {
+		t.Fatalf("deck.NewStacked err == %q", err)
+	}
+	seats := []holdem.Seat{{Name: "A", Stack: 100}, {Name: "B", Stack: 100}, {Name: "C", Stack: 100}}
+	h, err := holdem.NewHand(holdem.Config{SmallBlind: 1, BigBlind: 2}, seats, 0, d)
+	if err != nil {
+		t.Fatalf("holdem.NewHand err == %q", err)
+	}
+	return h
+}
+
+func act(t *testing.T, h *holdem.Hand, actions ...holdem.Action) {
+	for _, action := range actions {
+		if err := h.Act(action); err != nil {
+			t.Fatalf("Act(%+v) err == %q", action, err)
+		}
+	}
+}
+
+// answer plays a bot at the other end of conn, answering every state with
+// reply(state).
+func answer(conn net.Conn, reply func(state string) string) {
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		state := strings.TrimRight(scanner.Text(), "\r")
+		if answer := reply(state); answer != "" {
+			fmt.Fprintf(conn, "%s\r\n", answer)
+		}
+	}
+}
+
+func TestState(t *testing.T) {
+	h := newHand(t)
+	if state := State(h.View(0), 1); state != "MATCHSTATE:0:1::3c5c||" {
+		t.Errorf("State == %q but expected only its own cards and no actions", state)
+	}
+
+	act(t, h,
+		holdem.Action{Type: holdem.Raise, Amount: 6},
+		holdem.Action{Type: holdem.Call},
+		holdem.Action{Type: holdem.Call},
+		holdem.Action{Type: holdem.Check},
+		holdem.Action{Type: holdem.Bet, Amount: 10},
+		holdem.Action{Type: holdem.Fold},
+	)
+	if state := State(h.View(1), 7); state != "MATCHSTATE:1:7:r6cc/cr16f:|AhKh|/QhJhTh" {
+		t.Errorf("State == %q but expected MATCHSTATE:1:7:r6cc/cr16f:|AhKh|/QhJhTh", state)
+	}
+
+	act(t, h, holdem.Action{Type: holdem.Raise, Amount: 30}, holdem.Action{Type: holdem.Call})
+	if state := State(h.View(1), 7); state != "MATCHSTATE:1:7:r6cc/cr16fr36c/:|AhKh|/QhJhTh/9s" {
+		t.Errorf("State == %q but expected MATCHSTATE:1:7:r6cc/cr16fr36c/:|AhKh|/QhJhTh/9s", state)
+	}
+}
+
+func TestParse(t *testing.T) {
+	h := newHand(t)
+	view := h.View(h.ToAct())
+	for _, test := range []struct {
+		reply       string
+		expected    string
+		expectedErr string
+	}{
+		{"f", "{fold 0}", ""},
+		{"c", "{call 0}", ""},
+		{"r6", "{raise 6}", ""},
+		{"r100", "{raise 100}", ""},
+		{"r3", "", "Invalid action: r3 must put in from 4 to 100"},
+		{"r101", "", "Invalid action: r101 must put in from 4 to 100"},
+		{"rx", "", `Invalid action: must be f, c or r<N>, not "rx"`},
+		{"k", "", `Invalid action: must be f, c or r<N>, not "k"`},
+	} {
+		action, err := Parse(view, test.reply)
+		if test.expectedErr != "" {
+			if err == nil || err.Error() != test.expectedErr {
+				t.Errorf("Parse(%q) err == %v but expected %q", test.reply, err, test.expectedErr)
+			}
+			continue
+		}
+		if err != nil || fmt.Sprint(action) != test.expected {
+			t.Errorf("Parse(%q) == %v, %v but expected %s", test.reply, action, err, test.expected)
+		}
+	}
+}
+
+func TestBot(t *testing.T) {
+	server, client := net.Pipe()
+	states := make(chan string, 100)
+	done := make(chan struct{})
+	go func() {
+		answer(client, func(state string) string {
+			states <- state
+			if strings.HasPrefix(state, "MATCHSTATE:0:1::") {
+				fmt.Fprint(client, "VERSION:2.0.0\r\n")
+				return state + ":r10"
+			}
+			return state + ":c"
+		})
+		close(done)
+	}()
+	bot := New("pipe", server, server, server, time.Second)
+
+	h := newHand(t)
+	if err := sim.Play(h, []sim.Player{bot, caller{}, caller{}}); err != nil {
+		t.Fatalf("Play err == %q but expected nil", err)
+	}
+	if bot.Faults != 0 || bot.hand != 1 || h.Players()[1].Stack != 120 {
+		t.Errorf("Bot faults %d on hand %d, %v, with stacks %v but expected seat 1 to win the raised pot", bot.Faults, bot.hand, bot.Err, h.Players())
+	}
+
+	// The same cards and actions again are still a new hand.
+	sim.Play(newHand(t), []sim.Player{bot, caller{}, caller{}})
+	bot.Close()
+	<-done
+	close(states)
+
+	finals := []string{}
+	for state := range states {
+		if strings.Contains(state, "|AhKh|") {
+			finals = append(finals, state)
+		}
+	}
+	expected := "[MATCHSTATE:0:1:r10cc/ccc/ccc/ccc:3c5c|AhKh|2c4c/QhJhTh/9s/8s MATCHSTATE:0:2:ccc/ccc/ccc/ccc:3c5c|AhKh|2c4c/QhJhTh/9s/8s]"
+	if fmt.Sprint(finals) != expected {
+		t.Errorf("final states == %v but expected %s", finals, expected)
+	}
+}
+
+func TestStateFoldedBeforeTheFlop(t *testing.T) {
+	h := newHand(t)
+	act(t, h, holdem.Action{Type: holdem.Raise, Amount: 6}, holdem.Action{Type: holdem.Fold}, holdem.Action{Type: holdem.Fold})
+	if state := State(h.View(0), 3); state != "MATCHSTATE:0:3:r6ff:3c5c||" {
+		t.Errorf("State == %q but expected MATCHSTATE:0:3:r6ff:3c5c||", state)
+	}
+}
+
+func TestBotFolds(t *testing.T) {
+	for _, test := range []struct {
+		reply       func(state string) string
+		expectedErr string
+	}{
+		{func(state string) string { return "" }, "Invalid bot: test took longer than 50ms"},
+		{func(state string) string { return state + ":r1" }, "Invalid bot: test Invalid action: r1 must put in from 4 to 100"},
+		{func(state string) string { return "MATCHSTATE:0:0::||:c" }, "Invalid bot: test took longer than 50ms"},
+	} {
+		server, client := net.Pipe()
+		go answer(client, test.reply)
+		bot := New("test", server, server, server, 50*time.Millisecond)
+
+		h := newHand(t)
+		if action := bot.Act(h.View(0)); action.Type != holdem.Fold || bot.Faults != 1 || bot.Err.Error() != test.expectedErr {
+			t.Errorf("Act == %v with faults %d and err %v but expected a fold for %q", action, bot.Faults, bot.Err, test.expectedErr)
+		}
+		bot.Close()
+	}
+
+	server, client := net.Pipe()
+	client.Close()
+	bot := New("gone", server, server, server, 0)
+	if action := bot.Act(newHand(t).View(0)); action.Type != holdem.Fold || bot.Err == nil || !strings.HasPrefix(bot.Err.Error(), "Invalid bot: gone has gone") {
+		t.Errorf("Act == %v with err %v but expected a fold when the bot has gone", action, bot.Err)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	bot, err := Command("sed", time.Second, "sed", "-u", `s/\r$/:c/`)
+	if err != nil {
+		t.Skipf("sed did not start: %v", err)
+	}
+	defer bot.Close()
+
+	h := newHand(t)
+	if err := sim.Play(h, []sim.Player{bot, caller{}, caller{}}); err != nil || bot.Faults != 0 {
+		t.Errorf("Play err == %v with faults %d, %v, but expected the sed bot to call down", err, bot.Faults, bot.Err)
+	}
+}
+
+func TestAccept(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("net.Listen err == %q", err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		if err == nil {
+			answer(conn, func(state string) string { return state + ":c" })
+		}
+	}()
+
+	bot, err := Accept("tcp", time.Second, listener)
+	if err != nil {
+		t.Fatalf("Accept err == %q but expected nil", err)
+	}
+	defer bot.Close()
+	h := newHand(t)
+	if err := sim.Play(h, []sim.Player{bot, caller{}, caller{}}); err != nil || bot.Faults != 0 {
+		t.Errorf("Play err == %v with faults %d, %v, but expected the bot to call down", err, bot.Faults, bot.Err)
+	}
+}
@@ -0,0 +1,148 @@
+package sim // github.com/sildani/poker-hands-go/sim
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/holdem"
+	"math"
+)
+
+// Player decides for one seat. It is shown only what that seat may see.
+type Player interface {
+	Act(view holdem.View) holdem.Action
+}
+
+// Observer is a Player that is told when each hand it sits in starts and
+// ends, with its view then. The view at the end shows the hole cards of a
+// showdown.
+type Observer interface {
+	Player
+	StartHand(view holdem.View)
+	EndHand(view holdem.View)
+}
+
+// Play asks the players, by seat, for their actions until the hand is over,
+// telling any Observer when it starts and ends.
+func Play(h *holdem.Hand, players []Player) error {
+	for seat, player := range players {
+		if observer, ok := player.(Observer); ok {
+			observer.StartHand(h.View(seat))
+		}
+	}
+	for !h.Done() {
+		seat := h.ToAct()
+		action := players[seat].Act(h.View(seat))
+		if err := h.Act(action); err != nil {
+			return fmt.Errorf("Invalid bot: seat %d %v", seat, err)
+		}
+	}
+	for seat, player := range players {
+		if observer, ok := player.(Observer); ok {
+			observer.EndHand(h.View(seat))
+		}
+	}
+	return nil
+}
+
+// Config sets up a match. Every hand starts everyone with Stack chips and
+// moves the button on. With Duplicate set each deal is played once for
+// every way of rotating the players through the seats, so everyone gets
+// the same cards in the same spots and the luck of the deal cancels out.
+type Config struct {
+	Game      holdem.Config
+	Stack     int
+	Hands     int
+	Duplicate bool
+	Seed      int64
+}
+
+// Result is how a player did. BBPer100 is the big blinds won every hundred
+// hands, StdDev the standard deviation of the big blinds won over a
+// hundred hands and Error the half width of a 95% confidence interval
+// around BBPer100. In duplicate matches each set of rotations counts as
+// one sample.
+type Result struct {
+	Hands    int
+	Won      int
+	BBPer100 float64
+	StdDev   float64
+	Error    float64
+}
+
+// Run plays a match between the players, who take seats in order, and
+// reports how each did. In a duplicate match Hands counts every rotation.
+func Run(config Config, players []Player) ([]Result, error) {
+	if len(players) < 2 || len(players) > 10 {
+		return nil, errors.New("Invalid match: must have two to ten players")
+	}
+	if config.Hands <= 0 || config.Stack <= 0 {
+		return nil, errors.New("Invalid match: hands and stack must be positive")
+	}
+
+	rotations := 1
+	if config.Duplicate {
+		rotations = len(players)
+	}
+	seats := []holdem.Seat{}
+	for i := range players {
+		seats = append(seats, holdem.Seat{Name: fmt.Sprintf("Seat %d", i+1), Stack: config.Stack})
+	}
+
+	rng := deck.NewSeededRNG(config.Seed)
+	results := make([]Result, len(players))
+	samples := make([][]float64, len(players))
+	for deal := 0; deal*rotations < config.Hands; deal++ {
+		shuffled := deck.New(rng)
+		shuffled.Shuffle()
+		cards, _ := shuffled.Deal(52)
+		button := deal % len(players)
+
+		won := make([]int, len(players))
+		for rotation := 0; rotation < rotations; rotation++ {
+			// Player i sits in seat (i + rotation) % n.
+			seated := make([]Player, len(players))
+			for i, player := range players {
+				seated[(i+rotation)%len(players)] = player
+			}
+
+			d, _ := deck.NewStacked(cards)
+			h, err := holdem.NewHand(config.Game, seats, button, d)
+			if err != nil {
+				return nil, err
+			}
+			if err := Play(h, seated); err != nil {
+				return nil, err
+			}
+			for i := range players {
+				seat := (i + rotation) % len(players)
+				won[i] += h.Players()[seat].Stack - config.Stack
+				results[i].Hands++
+			}
+		}
+
+		for i := range players {
+			results[i].Won += won[i]
+			samples[i] = append(samples[i], float64(won[i])/float64(config.Game.BigBlind)/float64(rotations))
+		}
+	}
+
+	for i := range results {
+		mean, variance := 0.0, 0.0
+		for _, sample := range samples[i] {
+			mean += sample
+		}
+		mean /= float64(len(samples[i]))
+		for _, sample := range samples[i] {
+			variance += (sample - mean) * (sample - mean)
+		}
+		if len(samples[i]) > 1 {
+			variance /= float64(len(samples[i]) - 1)
+		}
+
+		results[i].BBPer100 = 100 * mean
+		results[i].StdDev = 10 * math.Sqrt(variance)
+		results[i].Error = 1.96 * 100 * math.Sqrt(variance/float64(len(samples[i])))
+	}
+	return results, nil
+}
@@ -0,0 +1,142 @@
+package sim // github.com/sildani/poker-hands-go/sim
+
+import (
+	"fmt"
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/holdem"
+	"testing"
+)
+
+// caller checks or calls every time.
+type caller struct{}
+
+func (caller) Act(view holdem.View) holdem.Action {
+	if view.Options.Check {
+		return holdem.Action{Type: holdem.Check}
+	}
+	return holdem.Action{Type: holdem.Call}
+}
+
+// raiser makes the smallest bet or raise it can.
+type raiser struct{}
+
+func (raiser) Act(view holdem.View) holdem.Action {
+	if view.CurrentBet == 0 {
+		return holdem.Action{Type: holdem.Bet, Amount: view.Options.MinRaise}
+	}
+	if view.Options.MinRaise == 0 {
+		return holdem.Action{Type: holdem.Call}
+	}
+	return holdem.Action{Type: holdem.Raise, Amount: view.Options.MinRaise}
+}
+
+// folder folds to any bet.
+type folder struct{}
+
+func (folder) Act(view holdem.View) holdem.Action {
+	if view.Options.Check {
+		return holdem.Action{Type: holdem.Check}
+	}
+	return holdem.Action{Type: holdem.Fold}
+}
+
+// better always bets 2, whether or not there is a bet.
+type better struct{}
+
+func (better) Act(view holdem.View) holdem.Action {
+	return holdem.Action{Type: holdem.Bet, Amount: 2}
+}
+
+// cheat peeks at everyone's cards, which it should not be able to.
+type cheat struct {
+	t *testing.T
+}
+
+func (c cheat) Act(view holdem.View) holdem.Action {
+	for i, p := range view.Players {
+		if i != view.Seat && len(p.Hole) > 0 {
+			c.t.Fatalf("seat %d saw seat %d's hole cards %v", view.Seat, i, p.Hole)
+		}
+	}
+	return caller{}.Act(view)
+}
+
+// watcher calls like caller and notes the hands it is told about.
+type watcher struct {
+	caller
+	started []int
+	ended   []holdem.View
+}
+
+func (w *watcher) StartHand(view holdem.View) {
+	w.started = append(w.started, view.Seat)
+}
+
+func (w *watcher) EndHand(view holdem.View) {
+	w.ended = append(w.ended, view)
+}
+
+func TestPlay(t *testing.T) {
+	seats := []holdem.Seat{{Name: "A", Stack: 100}, {Name: "B", Stack: 100}}
+	h, _ := holdem.NewHand(holdem.Config{SmallBlind: 1, BigBlind: 2}, seats, 0, ordered())
+	w := &watcher{}
+	if err := Play(h, []Player{caller{}, w}); err != nil {
+		t.Fatalf("Play err == %q but expected nil", err)
+	}
+	if fmt.Sprint(w.started) != "[1]" || len(w.ended) != 1 {
+		t.Fatalf("watcher started %v and ended %d hands but expected one hand in seat 1", w.started, len(w.ended))
+	}
+	if end := w.ended[0]; !h.Done() || len(end.Board) != 5 || fmt.Sprint(end.Players[0].Hole) != "[2H 4H]" {
+		t.Errorf("EndHand view has board %v and seat 0 holding %v but expected the showdown", end.Board, end.Players[0].Hole)
+	}
+}
+
+func TestRun(t *testing.T) {
+	config := Config{Game: holdem.Config{SmallBlind: 1, BigBlind: 2}, Stack: 200, Hands: 1000, Seed: 1}
+
+	// The folder gives up its small blind and its big blind to a raise.
+	results, err := Run(config, []Player{raiser{}, folder{}})
+	if err != nil {
+		t.Fatalf("Run err == %q but expected nil", err)
+	}
+	if results[0].BBPer100 != 75 || results[1].BBPer100 != -75 || results[1].Won != -1500 || results[0].Hands != 1000 {
+		t.Errorf("Run == %+v but expected 75 bb/100 to the raiser", results)
+	}
+	if results[0].StdDev < 2.49 || results[0].StdDev > 2.51 {
+		t.Errorf("StdDev == %.3f but expected 2.5", results[0].StdDev)
+	}
+
+	// Two calling stations break even in the long run but not hand to hand.
+	results, _ = Run(config, []Player{caller{}, cheat{t}})
+	if results[0].StdDev < 5 || results[0].BBPer100 != -results[1].BBPer100 {
+		t.Errorf("Run == %+v but expected a swingy zero-sum match", results)
+	}
+	if results[0].BBPer100 > results[0].Error || results[0].BBPer100 < -results[0].Error {
+		t.Errorf("BBPer100 == %.2f but expected zero within %.2f", results[0].BBPer100, results[0].Error)
+	}
+
+	// Dealt in duplicate, the luck cancels out exactly.
+	config.Duplicate = true
+	results, _ = Run(config, []Player{caller{}, caller{}})
+	if results[0].BBPer100 != 0 || results[0].StdDev != 0 || results[0].Hands != 1000 {
+		t.Errorf("duplicate Run == %+v but expected no swings at all", results)
+	}
+}
+
+func TestRunInvalid(t *testing.T) {
+	if _, err := Run(Config{Game: holdem.Config{SmallBlind: 1, BigBlind: 2}, Stack: 200, Hands: 10}, []Player{caller{}}); err == nil {
+		t.Errorf("Run with one player err == nil but expected an error")
+	}
+
+	// A bot that bets when there is already a bet is caught.
+	_, err := Run(Config{Game: holdem.Config{SmallBlind: 1, BigBlind: 2}, Stack: 200, Hands: 10}, []Player{better{}, caller{}})
+	if err == nil || err.Error() != "Invalid bot: seat 0 Invalid action: there is already a bet, so raise" {
+		t.Errorf("Run with a bad bot err == %v but expected its bet refused", err)
+	}
+}
+
+// ordered is a deck that deals the cards in order, hearts first.
+func ordered() *deck.Deck {
+	d, _ := deck.NewStacked(nil)
+	return d
+}
@@ -0,0 +1,129 @@
+package strength // github.com/sildani/poker-hands-go/strength
+
+import (
+	"errors"
+	"github.com/sildani/poker-hands-go/board"
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/evaluator"
+	"math"
+)
+
+const ahead = 0
+const tied = 1
+const behind = 2
+
+// HandStrength is the chance that hole is ahead of opponents random holdings
+// on board right now, counting a tie as half a win.
+func HandStrength(hole []string, boardCards []string, opponents int) (float64, error) {
+	if err := validate(hole, boardCards, opponents); err != nil {
+		return 0, err
+	}
+
+	cards := join(boardCards, hole)
+	score := evaluator.Score(cards)
+
+	var counts [3]float64
+	for _, holding := range board.Holdings(cards) {
+		counts[compare(score, evaluator.Score(join(boardCards, holding)))]++
+	}
+
+	strength := (counts[ahead] + counts[tied]/2) / (counts[ahead] + counts[tied] + counts[behind])
+	return math.Pow(strength, float64(opponents)), nil
+}
+
+// Potential returns the positive potential (the chance of ending up ahead
+// when behind now) and negative potential (the chance of ending up behind
+// when ahead now) against one opponent. With one card to come every river
+// is enumerated; with two, samples random holdings and runouts are dealt
+// with rng, which must not be nil.
+func Potential(hole []string, boardCards []string, samples int, rng deck.RNG) (float64, float64, error) {
+	if err := validate(hole, boardCards, 1); err != nil {
+		return 0, 0, err
+	}
+
+	var potential [3][3]float64
+	var totals [3]float64
+	cards := join(boardCards, hole)
+	score := evaluator.Score(cards)
+
+	count := func(holding []string, runout []string) {
+		now := compare(score, evaluator.Score(join(boardCards, holding)))
+		final := join(boardCards, runout)
+		later := compare(evaluator.Score(join(final, hole)), evaluator.Score(join(final, holding)))
+		potential[now][later]++
+		totals[now]++
+	}
+
+	switch len(boardCards) {
+	case 5:
+		return 0, 0, nil
+	case 4:
+		for _, holding := range board.Holdings(cards) {
+			for _, river := range board.Unseen(join(cards, holding)) {
+				count(holding, []string{river})
+			}
+		}
+	case 3:
+		if rng == nil {
+			return 0, 0, errors.New("Invalid rng: sampling runouts on the flop needs an RNG")
+		}
+		d := deck.New(rng)
+		d.Remove(cards...)
+		for i := 0; i < samples; i++ {
+			sample, _ := d.Sample(4)
+			count(sample[:2], sample[2:])
+		}
+	}
+
+	positive := 0.0
+	if totals[behind]+totals[tied] > 0 {
+		positive = (potential[behind][ahead] + potential[behind][tied]/2 + potential[tied][ahead]/2) /
+			(totals[behind] + totals[tied]/2)
+	}
+	negative := 0.0
+	if totals[ahead]+totals[tied] > 0 {
+		negative = (potential[ahead][behind] + potential[tied][behind]/2 + potential[ahead][tied]/2) /
+			(totals[ahead] + totals[tied]/2)
+	}
+	return positive, negative, nil
+}
+
+// EffectiveHandStrength combines hand strength against opponents with the
+// potential to improve or fall behind: HS * (1 - NPot) + (1 - HS) * PPot.
+func EffectiveHandStrength(hole []string, boardCards []string, opponents int, samples int, rng deck.RNG) (float64, error) {
+	strength, err := HandStrength(hole, boardCards, opponents)
+	if err != nil {
+		return 0, err
+	}
+	positive, negative, err := Potential(hole, boardCards, samples, rng)
+	if err != nil {
+		return 0, err
+	}
+	return strength*(1-negative) + (1-strength)*positive, nil
+}
+
+func validate(hole []string, boardCards []string, opponents int) error {
+	if len(hole) != 2 {
+		return errors.New("Invalid hole cards: must have two cards")
+	}
+	if len(boardCards) < 3 || len(boardCards) > 5 {
+		return errors.New("Invalid board: must have three to five cards")
+	}
+	if opponents < 1 {
+		return errors.New("Invalid opponents: must have at least one opponent")
+	}
+	return nil
+}
+
+func compare(score int, other int) int {
+	if score > other {
+		return ahead
+	} else if score == other {
+		return tied
+	}
+	return behind
+}
+
+func join(cards []string, more []string) []string {
+	return append(append(make([]string, 0, len(cards)+len(more)), cards...), more...)
+}
@@ -0,0 +1,108 @@
+package strength // github.com/sildani/poker-hands-go/strength
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestHandStrength(t *testing.T) {
+	tests := []struct {
+		hole             []string
+		board            []string
+		opponents        int
+		expectedStrength float64
+	}{
+		// The worked example from Billings et al., "The challenge of poker"
+		{[]string{"AD", "QC"}, []string{"3H", "4C", "JH"}, 1, 0.585},
+		{[]string{"AD", "QC"}, []string{"3H", "4C", "JH"}, 5, 0.069},
+		{[]string{"KH", "QH"}, []string{"AH", "JH", "TH"}, 3, 1},
+		{[]string{"2C", "3C"}, []string{"AS", "KS", "QS", "JS", "TS"}, 1, 0.5},
+	}
+
+	for _, test := range tests {
+		strength, err := HandStrength(test.hole, test.board, test.opponents)
+		if err != nil {
+			t.Errorf("HandStrength(%v, %v, %d) err == %q but expected nil", test.hole, test.board, test.opponents, err)
+		}
+		if math.Abs(strength-test.expectedStrength) > 0.001 {
+			t.Errorf("HandStrength(%v, %v, %d) == %.3f but expected %.3f",
+				test.hole, test.board, test.opponents, strength, test.expectedStrength)
+		}
+	}
+}
+
+func TestPotential(t *testing.T) {
+	tests := []struct {
+		hole              []string
+		board             []string
+		expectedPositive  float64
+		expectedNegative  float64
+		expectedTolerance float64
+	}{
+		// Two cards to come are sampled; the paper enumerates PPot 0.208, NPot 0.274
+		{[]string{"AD", "QC"}, []string{"3H", "4C", "JH"}, 0.208, 0.274, 0.02},
+		{[]string{"AD", "QC"}, []string{"3H", "4C", "JH", "8S"}, 0.105, 0.167, 0.001},
+		{[]string{"AD", "QC"}, []string{"3H", "4C", "JH", "8S", "2D"}, 0, 0, 0},
+	}
+
+	for _, test := range tests {
+		positive, negative, err := Potential(test.hole, test.board, 20000, rand.New(rand.NewSource(1)))
+		if err != nil {
+			t.Errorf("Potential(%v, %v) err == %q but expected nil", test.hole, test.board, err)
+		}
+		if math.Abs(positive-test.expectedPositive) > test.expectedTolerance {
+			t.Errorf("Potential(%v, %v) positive == %.3f but expected %.3f",
+				test.hole, test.board, positive, test.expectedPositive)
+		}
+		if math.Abs(negative-test.expectedNegative) > test.expectedTolerance {
+			t.Errorf("Potential(%v, %v) negative == %.3f but expected %.3f",
+				test.hole, test.board, negative, test.expectedNegative)
+		}
+	}
+}
+
+func TestPotentialWithoutRNG(t *testing.T) {
+	_, _, err := Potential([]string{"AD", "QC"}, []string{"3H", "4C", "JH"}, 100, nil)
+	if err == nil || err.Error() != "Invalid rng: sampling runouts on the flop needs an RNG" {
+		t.Errorf("Potential on the flop with no RNG err == %v but expected the RNG asked for", err)
+	}
+}
+
+func TestEffectiveHandStrength(t *testing.T) {
+	hole := []string{"AD", "QC"}
+	board := []string{"3H", "4C", "JH", "8S"}
+
+	strength, _ := HandStrength(hole, board, 1)
+	positive, negative, _ := Potential(hole, board, 0, nil)
+	expected := strength*(1-negative) + (1-strength)*positive
+
+	effective, err := EffectiveHandStrength(hole, board, 1, 0, nil)
+	if err != nil {
+		t.Errorf("EffectiveHandStrength(%v, %v) err == %q but expected nil", hole, board, err)
+	}
+	if effective != expected {
+		t.Errorf("EffectiveHandStrength(%v, %v) == %f but expected %f", hole, board, effective, expected)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	tests := []struct {
+		hole        []string
+		board       []string
+		opponents   int
+		expectedErr string
+	}{
+		{[]string{"AD"}, []string{"3H", "4C", "JH"}, 1, "Invalid hole cards: must have two cards"},
+		{[]string{"AD", "QC"}, []string{"3H", "4C"}, 1, "Invalid board: must have three to five cards"},
+		{[]string{"AD", "QC"}, []string{"3H", "4C", "JH"}, 0, "Invalid opponents: must have at least one opponent"},
+	}
+
+	for _, test := range tests {
+		_, err := HandStrength(test.hole, test.board, test.opponents)
+		if err == nil || err.Error() != test.expectedErr {
+			t.Errorf("HandStrength(%v, %v, %d) err == %v but expected %q",
+				test.hole, test.board, test.opponents, err, test.expectedErr)
+		}
+	}
+}
@@ -0,0 +1,404 @@
+package stud // github.com/sildani/poker-hands-go/stud
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sildani/poker-hands-go/betting"
+	"github.com/sildani/poker-hands-go/deck"
+	"github.com/sildani/poker-hands-go/evaluator"
+	"github.com/sildani/poker-hands-go/parser"
+	"github.com/sildani/poker-hands-go/pot"
+)
+
+const (
+	Third = iota
+	Fourth
+	Fifth
+	Sixth
+	Seventh
+	Showdown
+)
+
+const Fold = betting.Fold
+const Check = betting.Check
+const Call = betting.Call
+const Bet = betting.Bet
+const Raise = betting.Raise
+
+// Variants dealt the same way. Razz plays for the best ace-to-five low, so
+// the highest up card brings it in and the best low showing acts first.
+// Hi-lo splits each pot with the best eight or better low.
+const Stud = "stud"
+const Razz = "razz"
+const StudHiLo = "stud hi-lo"
+
+// Events that are not player decisions.
+const Ante = "ante"
+const BringIn = "bring-in"
+const Return = betting.Return
+const Win = betting.Win
+
+// The bring-in goes to the lowest suit when up cards tie: clubs, diamonds,
+// hearts, spades.
+var suitRanks = map[string]int{"C": 0, "D": 1, "H": 2, "S": 3}
+
+// Config sets the variant, stud unless set, and the fixed-limit stakes: the small bet on third and fourth
+// street, the big bet after. The bring-in is at most the small bet, and
+// the seat bringing it in may complete to the small bet instead. Cap
+// limits the bets and raises on a street, zero for none. OddChip is the pot
+// package rule for odd chips, the highest suit unless set.
+type Config struct {
+	Variant  string
+	Ante     int
+	BringIn  int
+	SmallBet int
+	BigBet   int
+	Cap      int
+	OddChip  string
+}
+
+type Seat struct {
+	Name  string
+	Stack int
+}
+
+type Player struct {
+	Name string
+	Down []string
+	Up   []string
+	betting.Chips
+}
+
+// Action is a decision by the player to act. For a bet or raise Amount is
+// the total the player's bet comes to on this street.
+type Action struct {
+	Type   string
+	Amount int
+}
+
+// Event is something that happened in the hand. For a call Amount is what
+// was put in; for a bet or raise it is the total bet on this street.
+type Event = betting.Event
+
+// Options are the legal actions for the player to act, worked out by the
+// betting package.
+type Options = betting.Options
+
+// Hand is a seven-card stud or razz hand as a state machine: NewHand posts the
+// antes and deals third street, the first Act brings it in or completes,
+// then each Act moves the hand on, dealing each street and settling the pot
+// once the betting is done.
+type Hand struct {
+	config    Config
+	players   []*Player
+	dealer    int
+	deck      *deck.Deck
+	community []string
+	table     betting.Table
+	pots      []pot.Pot
+	bringIn   int
+}
+
+func NewHand(config Config, seats []Seat, dealer int, d *deck.Deck) (*Hand, error) {
+	if len(seats) < 2 || len(seats) > 8 {
+		return nil, errors.New("Invalid table: must have two to eight players")
+	}
+	if config.SmallBet <= 0 || config.BigBet < config.SmallBet || config.BringIn <= 0 ||
+		config.BringIn > config.SmallBet || config.Ante < 0 {
+		return nil, errors.New("Invalid stakes: bring-in must be positive and at most the small bet, at most the big bet")
+	}
+	if dealer < 0 || dealer >= len(seats) {
+		return nil, fmt.Errorf("Invalid dealer: must be a seat from 0 to %d", len(seats)-1)
+	}
+	if config.Variant != "" && config.Variant != Stud && config.Variant != Razz && config.Variant != StudHiLo {
+		return nil, fmt.Errorf("Invalid variant: unknown game %q", config.Variant)
+	}
+	if config.OddChip == "" {
+		config.OddChip = pot.HighestSuit
+	}
+
+	h := &Hand{config: config, dealer: dealer, deck: d, community: []string{}}
+	chips := []*betting.Chips{}
+	for _, seat := range seats {
+		if seat.Stack <= 0 {
+			return nil, fmt.Errorf("Invalid seat: %s has no chips", seat.Name)
+		}
+		p := &Player{Name: seat.Name, Down: []string{}, Up: []string{}, Chips: betting.Chips{Stack: seat.Stack}}
+		h.players = append(h.players, p)
+		chips = append(chips, &p.Chips)
+	}
+	h.table = betting.NewTable(chips)
+
+	if config.Ante > 0 {
+		for i := range h.players {
+			h.table.Post(i, Ante, config.Ante)
+		}
+		for _, p := range h.players {
+			p.Bet = 0
+		}
+	}
+
+	for round := 0; round < 3; round++ {
+		for i := range h.players {
+			card, err := d.Deal(1)
+			if err != nil {
+				return nil, err
+			}
+			p := h.players[(dealer+1+i)%len(h.players)]
+			if round < 2 {
+				p.Down = append(p.Down, card[0])
+			} else {
+				p.Up = append(p.Up, card[0])
+			}
+		}
+	}
+
+	h.bringIn = h.BringInSeat()
+	h.table.Round = betting.NewRound(betting.FixedLimit, config.SmallBet, config.Cap)
+	h.table.ToAct = h.bringIn
+	return h, nil
+}
+
+// BringInSeat is the seat showing the lowest up card on third street, aces
+// high, with ties going to the lowest suit. In razz it is the highest, aces
+// low, with ties going to the highest suit.
+func (h *Hand) BringInSeat() int {
+	lowest, lowestRank := 0, 0
+	for seat, p := range h.players {
+		card := p.Up[0]
+		value, _ := parser.ParseCardValue(card[:1])
+		rank := value*4 + suitRanks[card[1:]]
+		if h.config.Variant == Razz {
+			rank = -(value%14)*4 - suitRanks[card[1:]]
+		}
+		if seat == 0 || rank < lowestRank {
+			lowest, lowestRank = seat, rank
+		}
+	}
+	return lowest
+}
+
+// FirstToAct is the seat showing the best up cards from fourth street on,
+// ranked as partial hands by evaluator.Score, or evaluator.LowScore in
+// razz. Ties go to the seat nearest the dealer's left.
+func (h *Hand) FirstToAct() int {
+	best, bestScore := -1, 0
+	for i := 1; i <= len(h.players); i++ {
+		seat := (h.dealer + i) % len(h.players)
+		p := h.players[seat]
+		if p.Folded {
+			continue
+		}
+		score := evaluator.Score(p.Up)
+		if h.config.Variant == Razz {
+			score = evaluator.LowScore(p.Up)
+		}
+		if best == -1 || score > bestScore {
+			best, bestScore = seat, score
+		}
+	}
+	return best
+}
+
+func (h *Hand) Players() []Player {
+	players := []Player{}
+	for _, p := range h.players {
+		player := *p
+		player.Down = append([]string{}, p.Down...)
+		player.Up = append([]string{}, p.Up...)
+		players = append(players, player)
+	}
+	return players
+}
+
+func (h *Hand) Dealer() int {
+	return h.dealer
+}
+
+// Community is the one card dealt face up for everyone on seventh street
+// when the deck runs short, otherwise empty.
+func (h *Hand) Community() []string {
+	return append([]string{}, h.community...)
+}
+
+func (h *Hand) Street() int {
+	return h.table.Street
+}
+
+// ToAct is the seat to act, or -1 once the hand is over.
+func (h *Hand) ToAct() int {
+	if h.Done() {
+		return -1
+	}
+	return h.table.ToAct
+}
+
+func (h *Hand) Done() bool {
+	return h.table.Street == Showdown
+}
+
+func (h *Hand) CurrentBet() int {
+	return h.table.Round.CurrentBet
+}
+
+func (h *Hand) Pot() int {
+	return h.table.Pot()
+}
+
+func (h *Hand) Events() []Event {
+	return append([]Event{}, h.table.Events...)
+}
+
+// Pots are the main pot and any side pots once the hand is over. Win
+// events come in the same order.
+func (h *Hand) Pots() []pot.Pot {
+	return append([]pot.Pot{}, h.pots...)
+}
+
+// Winnings is what each seat took from the pot once the hand is over.
+func (h *Hand) Winnings() []int {
+	return append([]int{}, h.table.Winnings...)
+}
+
+// Options are the legal actions for the seat to act. For the bring-in the
+// call is the bring-in and the raise is completing to the small bet.
+func (h *Hand) Options() Options {
+	if h.Done() {
+		return Options{}
+	}
+	options := h.table.Options()
+	if h.bringIn >= 0 {
+		options.Check = false
+		options.Call = min(h.config.BringIn, h.players[h.bringIn].Stack)
+		if options.MinRaise <= options.Call {
+			options.MinRaise, options.MaxRaise = 0, 0
+		}
+	}
+	return options
+}
+
+func (h *Hand) Act(action Action) error {
+	if h.Done() {
+		return errors.New("Invalid action: the hand is over")
+	}
+	if h.bringIn >= 0 {
+		return h.bringInOrComplete(action)
+	}
+	p := h.players[h.table.ToAct]
+	if err := h.table.Act(p.Name, action.Type, action.Amount, h.Options()); err != nil {
+		return err
+	}
+	h.advance()
+	return nil
+}
+
+// bringInOrComplete takes the bring-in seat's choice: a call or BringIn
+// posts the bring-in, and a bet or raise to the small bet completes.
+func (h *Hand) bringInOrComplete(action Action) error {
+	seat, p := h.bringIn, h.players[h.bringIn]
+	options := h.Options()
+	switch action.Type {
+	case Call, BringIn:
+		h.table.Post(seat, BringIn, options.Call)
+		h.table.Round.CurrentBet = p.Bet
+		if p.Bet == h.config.SmallBet {
+			h.table.Round.FullBet = h.config.SmallBet
+			h.table.Round.Raises = 1
+		}
+		h.table.Acted[seat] = true
+		h.table.FacedBet[seat] = p.Bet
+	case Bet, Raise:
+		if err := h.table.Act(p.Name, Bet, action.Amount, options); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("Invalid action: %s must bring it in for %d or complete to %d", p.Name, options.Call, h.config.SmallBet)
+	}
+	h.bringIn = -1
+	h.advance()
+	return nil
+}
+
+// advance moves on to the next player to act, dealing the next street or
+// settling the hand when the betting is done.
+func (h *Hand) advance() {
+	for !h.Done() {
+		if h.table.Contenders() == 1 {
+			h.table.ReturnUncalled()
+			h.settle()
+			return
+		}
+		if h.table.Next() {
+			return
+		}
+
+		h.table.ReturnUncalled()
+		if h.table.Street == Seventh {
+			h.settle()
+			return
+		}
+		h.nextStreet()
+	}
+}
+
+// nextStreet burns and deals a card to each player still in, face down on
+// seventh street and up before it. With too few cards left for seventh
+// street there is no burn and one card is dealt face up for everyone.
+func (h *Hand) nextStreet() {
+	betSize := h.config.BigBet
+	if h.table.Street+1 == Fourth {
+		betSize = h.config.SmallBet
+	}
+	h.table.NextStreet(betting.NewRound(betting.FixedLimit, betSize, h.config.Cap))
+
+	if h.table.Street == Seventh && h.deck.Remaining() <= h.table.Contenders() {
+		h.community, _ = h.deck.Deal(1)
+	} else {
+		h.deck.Burn()
+		for i := 1; i <= len(h.players); i++ {
+			p := h.players[(h.dealer+i)%len(h.players)]
+			if p.Folded {
+				continue
+			}
+			card, _ := h.deck.Deal(1)
+			if h.table.Street == Seventh {
+				p.Down = append(p.Down, card...)
+			} else {
+				p.Up = append(p.Up, card...)
+			}
+		}
+	}
+
+	// The best up cards act first; advance looks from the seat after ToAct.
+	h.table.ToAct = (h.FirstToAct() + len(h.players) - 1) % len(h.players)
+}
+
+// settle builds the main and side pots and awards each to the best hand
+// eligible for it, the best low in razz, or in hi-lo splits it with the
+// best low.
+func (h *Hand) settle() {
+	hands := [][]string{}
+	scores := make([]int, len(h.players))
+	lows := make([]int, len(h.players))
+	for i, p := range h.players {
+		cards := append(append(append([]string{}, p.Down...), p.Up...), h.community...)
+		hands = append(hands, cards)
+		if !p.Folded && h.table.Contenders() > 1 {
+			switch h.config.Variant {
+			case Razz:
+				scores[i] = evaluator.LowScore(cards)
+			case StudHiLo:
+				scores[i], lows[i] = evaluator.Score(cards), evaluator.EightOrBetter(cards)
+			default:
+				scores[i] = evaluator.Score(cards)
+			}
+		}
+	}
+
+	h.pots = h.table.Settle(Showdown, func(p pot.Pot) []int {
+		if h.config.Variant == StudHiLo {
+			return pot.AwardSplit([]pot.Pot{p}, scores, lows, hands, h.dealer, h.config.OddChip)
+		}
+		return pot.Award([]pot.Pot{p}, scores, hands, h.dealer, h.config.OddChip)
+	})
+}
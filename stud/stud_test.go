@@ -0,0 +1,238 @@
+package stud // github.com/sildani/poker-hands-go/stud
+
+import (
+	"fmt"
+	"github.com/sildani/poker-hands-go/deck"
+	"testing"
+)
+
+// stacked deals each seat's seven cards, in the order they are dealt to
+// it, starting left of the dealer, and burns 6D, 7D, 9D and TD before
+// fourth to seventh street. Nobody may fold before seventh street.
+func stacked(t *testing.T, dealer int, cards [][]string) *deck.Deck {
+	order := []string{}
+	burns := []string{"6D", "7D", "9D", "TD"}
+	for street := 0; street < 7; street++ {
+		if street >= 3 {
+			order = append(order, burns[street-3])
+		}
+		for i := range cards {
+			order = append(order, cards[(dealer+1+i)%len(cards)][street])
+		}
+	}
+	d, err := deck.NewStacked(order)
+	if err != nil {
+		t.Fatalf("deck.NewStacked(%v) err == %q", order, err)
+	}
+	return d
+}
+
+func seats(stacks ...int) []Seat {
+	seats := []Seat{}
+	for i, stack := range stacks {
+		seats = append(seats, Seat{Name: fmt.Sprintf("Player %d", i+1), Stack: stack})
+	}
+	return seats
+}
+
+func act(t *testing.T, h *Hand, actions ...Action) {
+	for _, action := range actions {
+		seat := h.ToAct()
+		if err := h.Act(action); err != nil {
+			t.Fatalf("seat %d Act(%v) err == %q but expected nil", seat, action, err)
+		}
+	}
+}
+
+func TestNewHandInvalid(t *testing.T) {
+	tests := []struct {
+		config      Config
+		seats       []Seat
+		expectedErr string
+	}{
+		{Config{BringIn: 3, SmallBet: 10, BigBet: 20}, seats(100), "Invalid table: must have two to eight players"},
+		{Config{BringIn: 15, SmallBet: 10, BigBet: 20}, seats(100, 100), "Invalid stakes: bring-in must be positive and at most the small bet, at most the big bet"},
+		{Config{BringIn: 3, SmallBet: 10, BigBet: 5}, seats(100, 100), "Invalid stakes: bring-in must be positive and at most the small bet, at most the big bet"},
+		{Config{BringIn: 3, SmallBet: 10, BigBet: 20}, seats(100, 0), "Invalid seat: Player 2 has no chips"},
+		{Config{Variant: "mississippi", BringIn: 3, SmallBet: 10, BigBet: 20}, seats(100, 100), "Invalid variant: unknown game \"mississippi\""},
+	}
+
+	for _, test := range tests {
+		_, err := NewHand(test.config, test.seats, 0, ordered())
+		if err == nil || err.Error() != test.expectedErr {
+			t.Errorf("NewHand(%v, %v) err == %v but expected %q", test.config, test.seats, err, test.expectedErr)
+		}
+	}
+}
+
+func TestActionOrder(t *testing.T) {
+	tests := []struct {
+		up              [][]string
+		dealer          int
+		expectedBringIn int
+		expectedFirst   int
+	}{
+		// The lowest card brings it in, clubs lowest on a tie.
+		{[][]string{{"KD"}, {"2C"}, {"2H"}}, 0, 1, 0},
+		{[][]string{{"AS"}, {"3S"}, {"KD"}}, 0, 1, 0},
+		// The best partial hand acts first, ties nearest the dealer's left.
+		{[][]string{{"KD", "9S"}, {"2C", "7C"}, {"2H", "2D"}}, 0, 1, 2},
+		{[][]string{{"KD", "9S"}, {"KS", "9D"}, {"2H", "7D"}}, 0, 2, 1},
+		{[][]string{{"KD", "9S"}, {"KS", "9D"}, {"2H", "7D"}}, 1, 2, 0},
+		{[][]string{{"KD", "9S", "9C"}, {"KS", "KC", "3D"}, {"2H", "7D", "8D"}}, 0, 2, 1},
+	}
+
+	for _, test := range tests {
+		h := &Hand{dealer: test.dealer}
+		for _, up := range test.up {
+			h.players = append(h.players, &Player{Up: up})
+		}
+		if seat := h.BringInSeat(); seat != test.expectedBringIn {
+			t.Errorf("BringInSeat() for %v == %d but expected %d", test.up, seat, test.expectedBringIn)
+		}
+		if seat := h.FirstToAct(); seat != test.expectedFirst {
+			t.Errorf("FirstToAct() for %v == %d but expected %d", test.up, seat, test.expectedFirst)
+		}
+	}
+}
+
+func TestBringInAndShowdown(t *testing.T) {
+	cards := [][]string{
+		{"AS", "AC", "KD", "9S", "KH", "3C", "4C"},
+		{"2S", "3S", "2C", "7C", "8H", "9H", "TH"},
+		{"4S", "5S", "2H", "2D", "8C", "JC", "QC"},
+	}
+	config := Config{Ante: 1, BringIn: 3, SmallBet: 10, BigBet: 20}
+	h, _ := NewHand(config, seats(100, 100, 100), 0, stacked(t, 0, cards))
+
+	if options := h.Options(); h.ToAct() != 1 || options.Check || options.Call != 3 || options.MinRaise != 10 {
+		t.Fatalf("seat %d to act with Options() %+v but expected the 2C to bring it in for 3 or complete to 10", h.ToAct(), options)
+	}
+	if err := h.Act(Action{Type: Fold}); err == nil || err.Error() != "Invalid action: Player 2 must bring it in for 3 or complete to 10" {
+		t.Errorf("Act(fold) by the bring-in err == %v but expected bring it in or complete", err)
+	}
+	act(t, h, Action{Type: BringIn})
+	if h.Pot() != 6 || h.ToAct() != 2 {
+		t.Fatalf("pot %d with seat %d to act but expected the 2C to bring it in for 3 and seat 2 to act", h.Pot(), h.ToAct())
+	}
+	if options := h.Options(); options.Call != 3 || options.MinRaise != 10 || options.MaxRaise != 10 {
+		t.Errorf("Options() == %+v but expected a call of 3 or completing to 10", options)
+	}
+	act(t, h, Action{Type: Raise, Amount: 10}, Action{Type: Call})
+	if options := h.Options(); h.ToAct() != 1 || options.Call != 7 || options.MinRaise != 20 {
+		t.Errorf("seat %d to act with Options() %+v but expected the bring-in to call 7 or raise to 20", h.ToAct(), options)
+	}
+	act(t, h, Action{Type: Call})
+
+	// The pair of deuces showing acts first on fourth street.
+	if h.Street() != Fourth || h.ToAct() != 2 || h.Options().MinRaise != 10 {
+		t.Errorf("street %d with seat %d to act and Options() %+v but expected seat 2 to bet 10 on fourth street",
+			h.Street(), h.ToAct(), h.Options())
+	}
+	act(t, h, Action{Type: Check}, Action{Type: Check}, Action{Type: Check})
+
+	// Kings showing beat deuces showing on fifth street, where the big bet
+	// starts.
+	if h.Street() != Fifth || h.ToAct() != 0 || h.Options().MinRaise != 20 {
+		t.Errorf("street %d with seat %d to act and Options() %+v but expected seat 0 to bet 20 on fifth street",
+			h.Street(), h.ToAct(), h.Options())
+	}
+	for street := Fifth; street <= Seventh; street++ {
+		act(t, h, Action{Type: Check}, Action{Type: Check}, Action{Type: Check})
+	}
+
+	players := h.Players()
+	if fmt.Sprint(players[0].Down) != "[AS AC 4C]" || fmt.Sprint(players[0].Up) != "[KD 9S KH 3C]" {
+		t.Errorf("seat 0 has %v down and %v up but expected [AS AC 4C] and [KD 9S KH 3C]", players[0].Down, players[0].Up)
+	}
+	if !h.Done() || fmt.Sprint(h.Winnings()) != "[33 0 0]" {
+		t.Errorf("Done() == %t with winnings %v but expected seat 0 to win 33", h.Done(), h.Winnings())
+	}
+}
+
+func TestBringInCompletes(t *testing.T) {
+	cards := [][]string{
+		{"AS", "AC", "KD", "9S", "KH", "3C", "4C"},
+		{"2S", "3S", "2C", "7C", "8H", "9H", "TH"},
+		{"4S", "5S", "2H", "2D", "8C", "JC", "QC"},
+	}
+	config := Config{Ante: 1, BringIn: 3, SmallBet: 10, BigBet: 20}
+	h, _ := NewHand(config, seats(100, 100, 100), 0, stacked(t, 0, cards))
+
+	act(t, h, Action{Type: Bet, Amount: 10})
+	if options := h.Options(); h.Pot() != 13 || h.ToAct() != 2 || options.Call != 10 || options.MinRaise != 20 {
+		t.Errorf("pot %d with seat %d to act and Options() %+v but expected seat 2 to call 10 or raise to 20 after the completion",
+			h.Pot(), h.ToAct(), options)
+	}
+	act(t, h, Action{Type: Call}, Action{Type: Call})
+	if h.Street() != Fourth || h.Pot() != 33 {
+		t.Errorf("street %d with pot %d but expected fourth street with 33 in", h.Street(), h.Pot())
+	}
+	if events := h.Events(); fmt.Sprint(events[3]) != "{1 0 bet 10}" {
+		t.Errorf("Events()[3] == %v but expected seat 1 to complete to 10", events[3])
+	}
+}
+
+func TestCommunityCard(t *testing.T) {
+	// Eight players and four burns leave one card for seventh street.
+	h, _ := NewHand(Config{BringIn: 1, SmallBet: 2, BigBet: 4}, seats(50, 50, 50, 50, 50, 50, 50, 50), 0, ordered())
+	for !h.Done() {
+		if h.Options().Check {
+			act(t, h, Action{Type: Check})
+		} else {
+			act(t, h, Action{Type: Call})
+		}
+	}
+
+	if len(h.Community()) != 1 || len(h.Players()[0].Down) != 2 {
+		t.Errorf("Community() == %v with %v down for seat 0 but expected one card for everyone",
+			h.Community(), h.Players()[0].Down)
+	}
+}
+
+func TestVariants(t *testing.T) {
+	cards := [][]string{
+		{"AS", "AC", "KD", "9S", "KH", "3C", "4C"},
+		{"2S", "3S", "2C", "7C", "8H", "9H", "6H"},
+		{"4S", "5S", "2H", "2D", "8C", "JC", "QC"},
+	}
+
+	tests := []struct {
+		variant          string
+		expectedBringIn  int
+		expectedFourth   int
+		expectedWinnings string
+	}{
+		// Kings up wins high; nobody has an eight or better low.
+		{Stud, 1, 2, "[12 0 0]"},
+		// The king brings it in, 7-2 showing acts first and 8-7-6-3-2 wins.
+		{Razz, 0, 1, "[0 12 0]"},
+		// Kings up wins high and 8-7-6-3-2 wins low.
+		{StudHiLo, 1, 2, "[6 6 0]"},
+	}
+
+	for _, test := range tests {
+		config := Config{Variant: test.variant, Ante: 1, BringIn: 3, SmallBet: 10, BigBet: 20}
+		h, _ := NewHand(config, seats(100, 100, 100), 0, stacked(t, 0, cards))
+		if seat := h.BringInSeat(); seat != test.expectedBringIn {
+			t.Errorf("%s: BringInSeat() == %d but expected %d", test.variant, seat, test.expectedBringIn)
+		}
+		act(t, h, Action{Type: Call}, Action{Type: Call}, Action{Type: Call})
+		if h.Street() != Fourth || h.ToAct() != test.expectedFourth {
+			t.Errorf("%s: street %d with seat %d to act but expected seat %d on fourth street",
+				test.variant, h.Street(), h.ToAct(), test.expectedFourth)
+		}
+		for !h.Done() {
+			act(t, h, Action{Type: Check})
+		}
+		if fmt.Sprint(h.Winnings()) != test.expectedWinnings {
+			t.Errorf("%s: Winnings() == %v but expected %s", test.variant, h.Winnings(), test.expectedWinnings)
+		}
+	}
+}
+
+// ordered is a deck that deals the cards in order, hearts first.
+func ordered() *deck.Deck {
+	d, _ := deck.NewStacked(nil)
+	return d
+}
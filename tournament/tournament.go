@@ -0,0 +1,179 @@
+package tournament // github.com/sildani/poker-hands-go/tournament
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sildani/poker-hands-go/holdem"
+	"io"
+	"time"
+)
+
+// Level is one step of a blind structure. It lasts Minutes, or Hands hands,
+// whichever comes first when both are set. A break has no blinds and lasts
+// Minutes; ColorUp is the chip it takes off the table, if any.
+type Level struct {
+	SmallBlind int  `json:"small_blind,omitempty"`
+	BigBlind   int  `json:"big_blind,omitempty"`
+	Ante       int  `json:"ante,omitempty"`
+	Minutes    int  `json:"minutes,omitempty"`
+	Hands      int  `json:"hands,omitempty"`
+	Break      bool `json:"break,omitempty"`
+	ColorUp    int  `json:"color_up,omitempty"`
+}
+
+// Structure is a blind structure as stored in a file.
+type Structure struct {
+	Levels []Level `json:"levels"`
+}
+
+// Clock tells the time, so tests can move it on by hand.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func NewSystemClock() Clock {
+	return systemClock{}
+}
+
+// Tournament keeps the level clock. Levels go up as their time runs out or
+// their hands are played; the last level lasts until the end. OnColorUp,
+// if set, is called with the chip to take off the table as the clock
+// enters a break that colors up, for the caller to apply ColorUp to the
+// stacks before the next hand.
+type Tournament struct {
+	OnColorUp func(denomination int)
+	levels    []Level
+	clock     Clock
+	level     int
+	started   time.Time
+	hands     int
+}
+
+// Load reads a structure as JSON and checks it.
+func Load(r io.Reader) (Structure, error) {
+	var structure Structure
+	if err := json.NewDecoder(r).Decode(&structure); err != nil {
+		return Structure{}, fmt.Errorf("Invalid structure: %v", err)
+	}
+	return structure, Validate(structure)
+}
+
+// Validate checks every level has blinds and an end, bar the last, which
+// may go on forever, and every break lasts some minutes.
+func Validate(structure Structure) error {
+	if len(structure.Levels) == 0 {
+		return errors.New("Invalid structure: must have at least one level")
+	}
+	for i, level := range structure.Levels {
+		if level.Break {
+			if level.Minutes <= 0 || level.Hands > 0 || level.BigBlind > 0 {
+				return fmt.Errorf("Invalid structure: break at level %d must last some minutes and have no blinds", i+1)
+			}
+			continue
+		}
+		if level.BigBlind <= 0 || level.SmallBlind < 0 || level.SmallBlind > level.BigBlind || level.Ante < 0 {
+			return fmt.Errorf("Invalid structure: level %d big blind must be positive and at least the small blind", i+1)
+		}
+		if level.Minutes < 0 || level.Hands < 0 || (level.Minutes == 0 && level.Hands == 0 && i < len(structure.Levels)-1) {
+			return fmt.Errorf("Invalid structure: level %d must last some minutes or hands", i+1)
+		}
+	}
+	return nil
+}
+
+// New starts the clock on the first level.
+func New(structure Structure, clock Clock) (*Tournament, error) {
+	if err := Validate(structure); err != nil {
+		return nil, err
+	}
+	return &Tournament{levels: structure.Levels, clock: clock, started: clock.Now()}, nil
+}
+
+// Level is the current level, counting from zero.
+func (t *Tournament) Level() int {
+	t.catchUp()
+	return t.level
+}
+
+// Current is the current level's blinds, or the break.
+func (t *Tournament) Current() Level {
+	t.catchUp()
+	return t.levels[t.level]
+}
+
+func (t *Tournament) OnBreak() bool {
+	return t.Current().Break
+}
+
+// Remaining is the time left in the current level, zero if it goes by
+// hands only or is the last.
+func (t *Tournament) Remaining() time.Duration {
+	level := t.Current()
+	if level.Minutes == 0 || t.level == len(t.levels)-1 {
+		return 0
+	}
+	return t.started.Add(time.Duration(level.Minutes) * time.Minute).Sub(t.clock.Now())
+}
+
+// HandPlayed counts a hand towards the current level.
+func (t *Tournament) HandPlayed() {
+	t.catchUp()
+	t.hands++
+	if level := t.levels[t.level]; level.Hands > 0 && t.hands >= level.Hands && t.level < len(t.levels)-1 {
+		t.next(t.clock.Now())
+	}
+}
+
+// Holdem returns config with the current blinds and ante, for the next
+// hand.
+func (t *Tournament) Holdem(config holdem.Config) holdem.Config {
+	level := t.Current()
+	config.SmallBlind, config.BigBlind, config.Ante = level.SmallBlind, level.BigBlind, level.Ante
+	return config
+}
+
+// catchUp moves on through any levels whose time has run out, each starting
+// when the one before was due to end.
+func (t *Tournament) catchUp() {
+	now := t.clock.Now()
+	for t.level < len(t.levels)-1 {
+		level := t.levels[t.level]
+		end := t.started.Add(time.Duration(level.Minutes) * time.Minute)
+		if level.Minutes == 0 || now.Before(end) {
+			return
+		}
+		t.next(end)
+	}
+}
+
+func (t *Tournament) next(started time.Time) {
+	t.level++
+	t.started = started
+	t.hands = 0
+	if level := t.levels[t.level]; level.Break && level.ColorUp > 0 && t.OnColorUp != nil {
+		t.OnColorUp(level.ColorUp)
+	}
+}
+
+// ColorUp takes chips smaller than denomination off the table: each stack
+// is rounded to the nearest multiple of it, half or more rounding up, but
+// nobody is colored out of the tournament.
+func ColorUp(stacks []int, denomination int) []int {
+	colored := []int{}
+	for _, stack := range stacks {
+		remainder := stack % denomination
+		stack -= remainder
+		if 2*remainder >= denomination || (stack == 0 && remainder > 0) {
+			stack += denomination
+		}
+		colored = append(colored, stack)
+	}
+	return colored
+}
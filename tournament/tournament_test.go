@@ -0,0 +1,108 @@
+package tournament // github.com/sildani/poker-hands-go/tournament
+
+import (
+	"fmt"
+	"github.com/sildani/poker-hands-go/holdem"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(minutes int) {
+	c.now = c.now.Add(time.Duration(minutes) * time.Minute)
+}
+
+const structure = `{"levels": [
+	{"small_blind": 25, "big_blind": 50, "minutes": 20},
+	{"small_blind": 50, "big_blind": 100, "minutes": 20, "hands": 3},
+	{"break": true, "minutes": 10, "color_up": 25},
+	{"small_blind": 100, "big_blind": 200, "ante": 25, "minutes": 20}
+]}`
+
+func TestLoad(t *testing.T) {
+	s, err := Load(strings.NewReader(structure))
+	if err != nil {
+		t.Fatalf("Load err == %q but expected nil", err)
+	}
+	if len(s.Levels) != 4 || s.Levels[2].ColorUp != 25 || s.Levels[3].Ante != 25 {
+		t.Errorf("Load == %+v but expected four levels with a break", s)
+	}
+
+	tests := []struct {
+		input       string
+		expectedErr string
+	}{
+		{`{"levels": []}`, "Invalid structure: must have at least one level"},
+		{`{"levels": [{"small_blind": 50, "big_blind": 25, "minutes": 20}]}`, "Invalid structure: level 1 big blind must be positive and at least the small blind"},
+		{`{"levels": [{"small_blind": 25, "big_blind": 50}, {"small_blind": 50, "big_blind": 100}]}`, "Invalid structure: level 1 must last some minutes or hands"},
+		{`{"levels": [{"break": true, "hands": 10}]}`, "Invalid structure: break at level 1 must last some minutes and have no blinds"},
+		{`{"levels": [`, "Invalid structure: unexpected EOF"},
+	}
+	for _, test := range tests {
+		if _, err := Load(strings.NewReader(test.input)); err == nil || err.Error() != test.expectedErr {
+			t.Errorf("Load(%s) err == %v but expected %q", test.input, err, test.expectedErr)
+		}
+	}
+}
+
+func TestLevelsByTimeAndHands(t *testing.T) {
+	s, _ := Load(strings.NewReader(structure))
+	clock := &fakeClock{time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC)}
+	tournament, _ := New(s, clock)
+	colorUps := []int{}
+	tournament.OnColorUp = func(denomination int) {
+		colorUps = append(colorUps, denomination)
+	}
+
+	clock.Advance(19)
+	if tournament.Level() != 0 || tournament.Remaining() != time.Minute {
+		t.Errorf("after 19 minutes level %d with %v left but expected level 0 with 1m0s left", tournament.Level(), tournament.Remaining())
+	}
+	clock.Advance(1)
+	if tournament.Level() != 1 {
+		t.Errorf("after 20 minutes level %d but expected level 1", tournament.Level())
+	}
+
+	// Three hands end the second level before its time is up.
+	for i := 0; i < 3; i++ {
+		tournament.HandPlayed()
+	}
+	if !tournament.OnBreak() || tournament.Remaining() != 10*time.Minute {
+		t.Errorf("after three hands OnBreak() == %t with %v left but expected a 10m0s break", tournament.OnBreak(), tournament.Remaining())
+	}
+	if fmt.Sprint(colorUps) != "[25]" {
+		t.Errorf("entering the break colored up %v but expected [25]", colorUps)
+	}
+
+	// Levels missed while nobody looked are caught up on, and the last
+	// level goes on.
+	clock.Advance(45)
+	config := tournament.Holdem(holdem.Config{OddChip: "left of button"})
+	if tournament.Level() != 3 {
+		t.Errorf("after 45 more minutes level %d but expected level 3", tournament.Level())
+	}
+	if config.SmallBlind != 100 || config.BigBlind != 200 || config.Ante != 25 {
+		t.Errorf("config blinds == %d/%d ante %d but expected 100/200 ante 25", config.SmallBlind, config.BigBlind, config.Ante)
+	}
+	if config.OddChip != "left of button" {
+		t.Errorf("config.OddChip == %q but expected it kept", config.OddChip)
+	}
+	if fmt.Sprint(colorUps) != "[25]" {
+		t.Errorf("after the break colored up %v but expected only [25]", colorUps)
+	}
+}
+
+func TestColorUp(t *testing.T) {
+	stacks := ColorUp([]int{1000, 1010, 1015, 10, 0}, 25)
+	if fmt.Sprint(stacks) != "[1000 1000 1025 25 0]" {
+		t.Errorf("ColorUp == %v but expected [1000 1000 1025 25 0]", stacks)
+	}
+}